@@ -1,13 +1,18 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
@@ -19,29 +24,58 @@ type Config struct {
 	KarmadaKubeconfig string
 	MgmtKubeconfig    string
 	DatabaseURL       string
+	SettingsFile      string
+	// Management cluster ConfigMap and Secret holding platform settings, as namespace/name
+	SettingsConfigMap string
+	SettingsSecret    string
 
-	// Kubernetes clients
-	KarmadaClient    *karmadaclientset.Clientset
-	KarmadaK8sClient *kubernetes.Clientset
-	MgmtClient       *kubernetes.Clientset
-	KarmadaConfig    *rest.Config
-	MgmtConfig       *rest.Config
+	// Platform settings loaded at startup; CurrentSettings returns the active ones
+	Settings *Settings
+
+	// Kubernetes clients. The Karmada clients are those of the default federation.
+	KarmadaClient        *karmadaclientset.Clientset
+	KarmadaK8sClient     *kubernetes.Clientset
+	KarmadaDynamicClient dynamic.Interface
+	MgmtClient           kubernetes.Interface
+	KarmadaConfig        *rest.Config
+	MgmtConfig           *rest.Config
+
+	// Federations holds the clients of every Karmada control plane, the default one first
+	Federations []*FederationClients
 
 	// Database
 	DB *gorm.DB
+
+	// Settings reloaded from the management cluster
+	fileSettings    *Settings
+	settingsMu      sync.RWMutex
+	currentSettings *Settings
+	settingsVersion SettingsVersion
+	settingsSynced  atomic.Bool
+	configMapLister corelisters.ConfigMapLister
+	secretLister    corelisters.SecretLister
+}
+
+// FederationClients are the clients of one Karmada control plane
+type FederationClients struct {
+	Name          string
+	Config        *rest.Config
+	KarmadaClient *karmadaclientset.Clientset
+	K8sClient     *kubernetes.Clientset
+	DynamicClient dynamic.Interface
 }
 
-// New creates a new configuration instance
-func New(karmadaKubeconfig, mgmtKubeconfig, databaseURL string) (*Config, error) {
+// New creates a new configuration instance. Platform settings come from the
+// settings file or the management cluster ConfigMap, plus the storage
+// credentials of the Secret; all are optional.
+func New(karmadaKubeconfig, mgmtKubeconfig, databaseURL, settingsFile, settingsConfigMap, settingsSecret string) (*Config, error) {
 	cfg := &Config{
 		KarmadaKubeconfig: karmadaKubeconfig,
 		MgmtKubeconfig:    mgmtKubeconfig,
 		DatabaseURL:       databaseURL,
-	}
-
-	// Initialize Karmada client
-	if err := cfg.initKarmadaClient(); err != nil {
-		return nil, fmt.Errorf("failed to initialize Karmada client: %w", err)
+		SettingsFile:      settingsFile,
+		SettingsConfigMap: settingsConfigMap,
+		SettingsSecret:    settingsSecret,
 	}
 
 	// Initialize management cluster client
@@ -49,6 +83,18 @@ func New(karmadaKubeconfig, mgmtKubeconfig, databaseURL string) (*Config, error)
 		return nil, fmt.Errorf("failed to initialize MGMT client: %w", err)
 	}
 
+	// Load platform settings
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := cfg.loadSettings(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	// Initialize Karmada client
+	if err := cfg.initKarmadaClient(); err != nil {
+		return nil, fmt.Errorf("failed to initialize Karmada client: %w", err)
+	}
+
 	// Initialize database
 	if err := cfg.initDatabase(); err != nil {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
@@ -58,30 +104,62 @@ func New(karmadaKubeconfig, mgmtKubeconfig, databaseURL string) (*Config, error)
 	return cfg, nil
 }
 
-// initKarmadaClient initializes the Karmada Kubernetes client
+// initKarmadaClient initializes the Karmada clients of the default federation and of the federations in the settings
 func (c *Config) initKarmadaClient() error {
-	config, err := clientcmd.BuildConfigFromFlags("", c.KarmadaKubeconfig)
+	federation, err := newFederationClients(c.Settings.DefaultFederationName(), c.KarmadaKubeconfig)
+	if err != nil {
+		return err
+	}
+	c.KarmadaConfig = federation.Config
+	c.KarmadaClient = federation.KarmadaClient
+	c.KarmadaK8sClient = federation.K8sClient
+	c.KarmadaDynamicClient = federation.DynamicClient
+	c.Federations = []*FederationClients{federation}
+
+	for _, settings := range c.Settings.Federations {
+		federation, err := newFederationClients(settings.Name, settings.Kubeconfig)
+		if err != nil {
+			return fmt.Errorf("federation %s: %w", settings.Name, err)
+		}
+		c.Federations = append(c.Federations, federation)
+	}
+
+	log.Printf("Karmada client initialized successfully (%d federations)", len(c.Federations))
+	return nil
+}
+
+// newFederationClients creates the clients of a Karmada control plane from its kubeconfig
+func newFederationClients(name, kubeconfig string) (*FederationClients, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
 	if err != nil {
-		return fmt.Errorf("failed to build Karmada config: %w", err)
+		return nil, fmt.Errorf("failed to build Karmada config: %w", err)
 	}
-	c.KarmadaConfig = config
 
 	// Create Karmada-specific clientset
 	karmadaClient, err := karmadaclientset.NewForConfig(config)
 	if err != nil {
-		return fmt.Errorf("failed to create Karmada clientset: %w", err)
+		return nil, fmt.Errorf("failed to create Karmada clientset: %w", err)
 	}
-	c.KarmadaClient = karmadaClient
 
 	// Create standard Kubernetes clientset for Karmada
 	k8sClient, err := kubernetes.NewForConfig(config)
 	if err != nil {
-		return fmt.Errorf("failed to create Kubernetes clientset for Karmada: %w", err)
+		return nil, fmt.Errorf("failed to create Kubernetes clientset for Karmada: %w", err)
 	}
-	c.KarmadaK8sClient = k8sClient
 
-	log.Println("Karmada client initialized successfully")
-	return nil
+	// Create dynamic client for RayJobs and other custom resources
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client for Karmada: %w", err)
+	}
+
+	return &FederationClients{
+		Name:          name,
+		Config:        config,
+		KarmadaClient: karmadaClient,
+		K8sClient:     k8sClient,
+		DynamicClient: dynamicClient,
+	}, nil
 }
 
 // initMgmtClient initializes the management cluster Kubernetes client
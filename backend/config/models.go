@@ -11,12 +11,18 @@ type TrainingJob struct {
 	ID             string `gorm:"primaryKey"`
 	JobName        string `gorm:"index"`
 	Namespace      string `gorm:"index"`
+	Federation     string `gorm:"index"` // Karmada control plane; empty for jobs from before federations
 	Algorithm      string `gorm:"index"` // algorithmName from request
 	Priority       int
 	RequestPayload string `gorm:"type:jsonb"` // Full request as JSON for reconstruction
 	TargetClusters string `gorm:"type:text"`  // JSON array of target cluster names
 	Status         string `gorm:"index"`
+	Admission      string // Queue admission state (Queued/Admitted) when a queueing integration is used
 	Message        string `gorm:"type:text"`
+	ArtifactURI    string `gorm:"type:text"` // Final artifact location, recorded when the job succeeds
+	Placement      string `gorm:"type:text"` // JSON array of the clusters Karmada scheduled the job to
+	FailoverEvents string `gorm:"type:text"` // JSON array of cluster failures that affected the job
+	Resources      string `gorm:"type:text"` // JSON array of the Kubernetes objects created for the job
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 	DeletedAt      gorm.DeletedAt `gorm:"index"`
@@ -0,0 +1,541 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// Settings holds platform settings loaded from the settings file
+type Settings struct {
+	// PriorityClasses maps job priorities to Kubernetes PriorityClasses and
+	// Karmada policy priority. The entry with the highest MinPriority not above
+	// the job's priority applies.
+	PriorityClasses []PriorityClassMapping `json:"priorityClasses,omitempty"`
+
+	// Queueing is the batch queueing integration for clusters without their own setting
+	Queueing *QueueingSettings `json:"queueing,omitempty"`
+
+	// Placement is the multi-cluster placement for jobs that do not set their own
+	Placement *models.Placement `json:"placement,omitempty"`
+
+	// Failover tunes how jobs are moved off failed clusters
+	Failover *FailoverSettings `json:"failover,omitempty"`
+
+	// ClusterSelection picks the member cluster for jobs without target clusters
+	ClusterSelection *ClusterSelectionSettings `json:"clusterSelection,omitempty"`
+
+	// Monitor tunes the job monitor; read at startup only
+	Monitor *MonitorSettings `json:"monitor,omitempty"`
+
+	// Clusters holds per-member-cluster settings keyed by cluster name
+	Clusters map[string]ClusterSettings `json:"clusters,omitempty"`
+
+	// Defaults are the images, Ray version, PVC and storage credentials of
+	// jobs that do not set their own
+	Defaults *JobDefaults `json:"defaults,omitempty"`
+
+	// DefaultFederation names the Karmada control plane of --karmada-kubeconfig
+	// (default "default"). Jobs without a federation run there.
+	DefaultFederation string `json:"defaultFederation,omitempty"`
+
+	// Federations are further Karmada control planes jobs can be sent to
+	Federations []FederationSettings `json:"federations,omitempty"`
+}
+
+// defaultFederationName names the control plane of --karmada-kubeconfig when
+// the settings do not
+const defaultFederationName = "default"
+
+// FederationSettings describes a Karmada control plane besides the default one
+type FederationSettings struct {
+	Name       string `json:"name"`
+	Kubeconfig string `json:"kubeconfig"` // Path to the control plane's kubeconfig
+	// Namespaces are sent to this federation when the job does not name one
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// Built-in job defaults for settings that do not set them
+const (
+	DefaultRayVersion = "2.46.0"
+	DefaultImage      = "kiepdoden123/iris-training-ray:v1.2"
+	DefaultPVCName    = "kham-pv-for-xgboost"
+	DefaultS3Region   = "us-east-1"
+)
+
+// JobDefaults are the platform defaults applied when converting jobs. Unset
+// fields keep the built-in defaults.
+type JobDefaults struct {
+	RayVersion string `json:"rayVersion,omitempty"`
+	// Image is the head and worker image of built-in algorithms without an entry in Images
+	Image string `json:"image,omitempty"`
+	// Images maps built-in algorithm names to their head and worker image
+	Images map[string]string `json:"images,omitempty"`
+	// CustomImage runs custom algorithms without their own image (default: rayproject/ray:<rayVersion>)
+	CustomImage string `json:"customImage,omitempty"`
+	// PVCName is the shared result storage claim of jobs without their own
+	PVCName string `json:"pvcName,omitempty"`
+	// Storage holds the object storage credentials handed to jobs. The
+	// settings Secret, when configured, overrides them.
+	Storage StorageCredentials `json:"storage,omitempty"`
+}
+
+// StorageCredentials are the S3 or MinIO credentials of the platform
+type StorageCredentials struct {
+	Region    string `json:"region,omitempty"`
+	AccessKey string `json:"accessKey,omitempty"`
+	SecretKey string `json:"secretKey,omitempty"`
+}
+
+// Configured reports whether both keys of the credentials are set
+func (c StorageCredentials) Configured() bool {
+	return c.AccessKey != "" && c.SecretKey != ""
+}
+
+// ImageFor returns the default head and worker image of a built-in algorithm
+func (d JobDefaults) ImageFor(algorithm string) string {
+	if image, ok := d.Images[algorithm]; ok && image != "" {
+		return image
+	}
+	return d.Image
+}
+
+// ClusterSettings holds settings that apply to one member cluster
+type ClusterSettings struct {
+	Queueing *QueueingSettings `json:"queueing,omitempty"`
+
+	// Overrides adapt every job propagated to the cluster, e.g. to its
+	// registry mirror, GPU node labels and storage classes
+	models.ClusterOverrides
+}
+
+// FailoverSettings tunes how Karmada moves jobs off failed clusters. Unset
+// fields keep the platform defaults.
+type FailoverSettings struct {
+	// ClusterTolerationSeconds is how long a job stays on a NotReady or
+	// unreachable cluster before Karmada evicts it
+	ClusterTolerationSeconds *int32 `json:"clusterTolerationSeconds,omitempty"`
+	// UnhealthyTolerationSeconds is how long a RayJob may stay unhealthy
+	// before application failover moves it
+	UnhealthyTolerationSeconds *int32 `json:"unhealthyTolerationSeconds,omitempty"`
+	// GracePeriodSeconds is how long an evicted copy is kept while the job
+	// starts elsewhere
+	GracePeriodSeconds *int32 `json:"gracePeriodSeconds,omitempty"`
+}
+
+// Cluster selection strategies
+const (
+	SelectionMostFree     = "most-free"
+	SelectionBinPack      = "bin-pack"
+	SelectionPreferRegion = "prefer-region"
+)
+
+// ClusterSelectionSettings chooses where jobs without target clusters run.
+// Only clusters with enough free capacity for the whole job are considered.
+type ClusterSelectionSettings struct {
+	// Strategy is "most-free" (default), "bin-pack" or "prefer-region"
+	Strategy string `json:"strategy,omitempty"`
+	// PreferredRegions are tried in order by prefer-region before any other region
+	PreferredRegions []string `json:"preferredRegions,omitempty"`
+	// Disabled leaves cluster choice and the fit check to Karmada
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// MonitorSettings tunes how the job monitor reconciles jobs. Unset fields
+// keep the defaults.
+type MonitorSettings struct {
+	// Workers is how many jobs are reconciled concurrently (default 4)
+	Workers int `json:"workers,omitempty"`
+	// ResyncSeconds is how often every active job is reconciled (default 60)
+	ResyncSeconds int `json:"resyncSeconds,omitempty"`
+	// PollSeconds is how often the jobs of federations that cannot be watched
+	// are reconciled (default 10)
+	PollSeconds int `json:"pollSeconds,omitempty"`
+	// MaxBackoffSeconds caps the retry delay of jobs whose status cannot be read (default 300)
+	MaxBackoffSeconds int `json:"maxBackoffSeconds,omitempty"`
+	// LostAfterFailures marks a job Lost once its status could not be read
+	// this many times in a row (default 10)
+	LostAfterFailures int `json:"lostAfterFailures,omitempty"`
+	// ClaimBindTimeoutSeconds fails a job whose PVC is still not bound in its
+	// clusters this long after it was submitted (default 600)
+	ClaimBindTimeoutSeconds int `json:"claimBindTimeoutSeconds,omitempty"`
+}
+
+// Queueing integrations
+const (
+	QueueingKueue   = "kueue"
+	QueueingVolcano = "volcano"
+)
+
+// QueueingSettings selects how jobs are queued and gang scheduled in a cluster
+type QueueingSettings struct {
+	Type string `json:"type"` // "kueue", "volcano" or empty for none
+
+	// Kueue: LocalQueue per namespace, with a fallback for unmapped namespaces
+	LocalQueues       map[string]string `json:"localQueues,omitempty"`
+	DefaultLocalQueue string            `json:"defaultLocalQueue,omitempty"`
+
+	// Volcano: scheduler name (defaults to "volcano") and optional queue
+	SchedulerName string `json:"schedulerName,omitempty"`
+	VolcanoQueue  string `json:"volcanoQueue,omitempty"`
+}
+
+// LocalQueueFor returns the Kueue LocalQueue for a namespace, or "" if none is configured
+func (q *QueueingSettings) LocalQueueFor(namespace string) string {
+	if queue, ok := q.LocalQueues[namespace]; ok {
+		return queue
+	}
+	return q.DefaultLocalQueue
+}
+
+// PriorityClassMapping describes how jobs at or above MinPriority are scheduled
+type PriorityClassMapping struct {
+	MinPriority       int    `json:"minPriority"`
+	PriorityClassName string `json:"priorityClassName,omitempty"` // Set on head and worker pods
+	PolicyPriority    *int32 `json:"policyPriority,omitempty"`    // PropagationPolicy spec.priority
+	Preemption        string `json:"preemption,omitempty"`        // PropagationPolicy spec.preemption: "Always" or "Never"
+}
+
+// LoadSettings reads settings from a YAML or JSON file. An empty path yields empty settings.
+func LoadSettings(path string) (*Settings, error) {
+	if path == "" {
+		return &Settings{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings file: %w", err)
+	}
+	settings, err := parseSettings(data)
+	if err != nil {
+		return nil, fmt.Errorf("settings file %s: %w", path, err)
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid settings file %s: %w", path, err)
+	}
+	return settings, nil
+}
+
+// parseSettings parses YAML or JSON settings, rejecting unknown fields
+func parseSettings(data []byte) (*Settings, error) {
+	settings := &Settings{}
+	if err := yaml.UnmarshalStrict(data, settings); err != nil {
+		return nil, fmt.Errorf("failed to parse settings: %w", err)
+	}
+	return settings, nil
+}
+
+// Validate checks the settings for values Kubernetes or Karmada would reject
+func (s *Settings) Validate() error {
+	for _, mapping := range s.PriorityClasses {
+		switch mapping.Preemption {
+		case "", "Always", "Never":
+		default:
+			return fmt.Errorf("priorityClasses: unsupported preemption %q (expected Always or Never)", mapping.Preemption)
+		}
+	}
+	if err := s.Placement.Validate(); err != nil {
+		return fmt.Errorf("placement: %w", err)
+	}
+	if err := s.Failover.validate(); err != nil {
+		return err
+	}
+	if err := s.ClusterSelection.validate(); err != nil {
+		return err
+	}
+	if err := s.Monitor.validate(); err != nil {
+		return err
+	}
+	if err := s.Queueing.validate("queueing"); err != nil {
+		return err
+	}
+	for name, cluster := range s.Clusters {
+		if err := cluster.Queueing.validate(fmt.Sprintf("clusters.%s.queueing", name)); err != nil {
+			return err
+		}
+		if err := cluster.ClusterOverrides.Validate(); err != nil {
+			return fmt.Errorf("clusters.%s: %w", name, err)
+		}
+	}
+	return s.validateFederations()
+}
+
+func (s *Settings) validateFederations() error {
+	names := map[string]bool{s.DefaultFederationName(): true}
+	namespaces := map[string]string{}
+	for i, federation := range s.Federations {
+		switch {
+		case federation.Name == "":
+			return fmt.Errorf("federations[%d]: name is required", i)
+		case names[federation.Name]:
+			return fmt.Errorf("federations[%d]: duplicate federation %q", i, federation.Name)
+		case federation.Kubeconfig == "":
+			return fmt.Errorf("federations.%s: kubeconfig is required", federation.Name)
+		}
+		names[federation.Name] = true
+
+		for _, namespace := range federation.Namespaces {
+			if other, ok := namespaces[namespace]; ok {
+				return fmt.Errorf("federations.%s: namespace %s is already sent to federation %s", federation.Name, namespace, other)
+			}
+			namespaces[namespace] = federation.Name
+		}
+	}
+	return nil
+}
+
+func (f *FailoverSettings) validate() error {
+	if f == nil {
+		return nil
+	}
+	for field, value := range map[string]*int32{
+		"clusterTolerationSeconds":   f.ClusterTolerationSeconds,
+		"unhealthyTolerationSeconds": f.UnhealthyTolerationSeconds,
+		"gracePeriodSeconds":         f.GracePeriodSeconds,
+	} {
+		if value != nil && *value < 0 {
+			return fmt.Errorf("failover.%s must not be negative", field)
+		}
+	}
+	return nil
+}
+
+func (c *ClusterSelectionSettings) validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.Strategy {
+	case "", SelectionMostFree, SelectionBinPack:
+	case SelectionPreferRegion:
+		if len(c.PreferredRegions) == 0 {
+			return fmt.Errorf("clusterSelection: prefer-region requires preferredRegions")
+		}
+	default:
+		return fmt.Errorf("clusterSelection: unsupported strategy %q (expected most-free, bin-pack or prefer-region)", c.Strategy)
+	}
+	return nil
+}
+
+func (m *MonitorSettings) validate() error {
+	if m == nil {
+		return nil
+	}
+	for field, value := range map[string]int{
+		"workers":           m.Workers,
+		"resyncSeconds":     m.ResyncSeconds,
+		"pollSeconds":       m.PollSeconds,
+		"maxBackoffSeconds": m.MaxBackoffSeconds,
+		"lostAfterFailures": m.LostAfterFailures,
+	} {
+		if value < 0 {
+			return fmt.Errorf("monitor.%s must not be negative", field)
+		}
+	}
+	return nil
+}
+
+func (q *QueueingSettings) validate(field string) error {
+	if q == nil {
+		return nil
+	}
+	switch q.Type {
+	case "", QueueingKueue, QueueingVolcano:
+		return nil
+	default:
+		return fmt.Errorf("%s: unsupported type %q (expected kueue or volcano)", field, q.Type)
+	}
+}
+
+// QueueingFor returns the queueing integration shared by the target clusters,
+// or nil when jobs there are not queued. Jobs without target clusters use the
+// default integration. Targets with different integrations are an error, since
+// one RayJob template is propagated to all of them.
+func (s *Settings) QueueingFor(targetClusters []string) (*QueueingSettings, error) {
+	if s == nil {
+		return nil, nil
+	}
+	if len(targetClusters) == 0 {
+		return s.Queueing.orNil(), nil
+	}
+
+	var selected *QueueingSettings
+	for i, name := range targetClusters {
+		queueing := s.Queueing
+		if cluster, ok := s.Clusters[name]; ok && cluster.Queueing != nil {
+			queueing = cluster.Queueing
+		}
+		queueing = queueing.orNil()
+
+		if i == 0 {
+			selected = queueing
+		} else if !reflect.DeepEqual(selected, queueing) {
+			return nil, fmt.Errorf("target clusters %s and %s use different queueing settings", targetClusters[0], name)
+		}
+	}
+	return selected, nil
+}
+
+// OverridesFor merges the per-cluster overrides from the settings with the
+// request's own, which win on conflicts. Only the target clusters are
+// included when the job has any; clusters without overrides are left out.
+func (s *Settings) OverridesFor(targetClusters []string, requested map[string]models.ClusterOverrides) map[string]models.ClusterOverrides {
+	clusters := map[string]bool{}
+	if s != nil {
+		for name := range s.Clusters {
+			clusters[name] = true
+		}
+	}
+	for name := range requested {
+		clusters[name] = true
+	}
+	if len(targetClusters) > 0 {
+		targets := map[string]bool{}
+		for _, name := range targetClusters {
+			targets[name] = clusters[name]
+		}
+		clusters = targets
+	}
+
+	overrides := map[string]models.ClusterOverrides{}
+	for name, ok := range clusters {
+		if !ok {
+			continue
+		}
+		var merged models.ClusterOverrides
+		if s != nil {
+			merged = s.Clusters[name].ClusterOverrides
+		}
+		merged = merged.Merge(requested[name])
+		if !merged.IsEmpty() {
+			overrides[name] = merged
+		}
+	}
+	return overrides
+}
+
+// PlacementFor returns the requested placement, or the default placement when the request has none
+func (s *Settings) PlacementFor(requested *models.Placement) *models.Placement {
+	if requested != nil || s == nil {
+		return requested
+	}
+	return s.Placement
+}
+
+// ClusterSelectionFor returns the cluster selection settings with the default strategy filled in
+func (s *Settings) ClusterSelectionFor() ClusterSelectionSettings {
+	selection := ClusterSelectionSettings{}
+	if s != nil && s.ClusterSelection != nil {
+		selection = *s.ClusterSelection
+	}
+	if selection.Strategy == "" {
+		selection.Strategy = SelectionMostFree
+	}
+	return selection
+}
+
+// orNil treats a queueing setting without a type as no queueing
+func (q *QueueingSettings) orNil() *QueueingSettings {
+	if q == nil || q.Type == "" {
+		return nil
+	}
+	return q
+}
+
+// MonitorFor returns the job monitor settings with the defaults filled in
+func (s *Settings) MonitorFor() MonitorSettings {
+	monitor := MonitorSettings{}
+	if s != nil && s.Monitor != nil {
+		monitor = *s.Monitor
+	}
+	if monitor.Workers == 0 {
+		monitor.Workers = 4
+	}
+	if monitor.ResyncSeconds == 0 {
+		monitor.ResyncSeconds = 60
+	}
+	if monitor.PollSeconds == 0 {
+		monitor.PollSeconds = 10
+	}
+	if monitor.MaxBackoffSeconds == 0 {
+		monitor.MaxBackoffSeconds = 300
+	}
+	if monitor.LostAfterFailures == 0 {
+		monitor.LostAfterFailures = 10
+	}
+	if monitor.ClaimBindTimeoutSeconds == 0 {
+		monitor.ClaimBindTimeoutSeconds = 600
+	}
+	return monitor
+}
+
+// JobDefaults returns the job defaults with the built-in defaults filled in
+func (s *Settings) JobDefaults() JobDefaults {
+	defaults := JobDefaults{}
+	if s != nil && s.Defaults != nil {
+		defaults = *s.Defaults
+	}
+	if defaults.RayVersion == "" {
+		defaults.RayVersion = DefaultRayVersion
+	}
+	if defaults.Image == "" {
+		defaults.Image = DefaultImage
+	}
+	if defaults.CustomImage == "" {
+		defaults.CustomImage = "rayproject/ray:" + defaults.RayVersion
+	}
+	if defaults.PVCName == "" {
+		defaults.PVCName = DefaultPVCName
+	}
+	if defaults.Storage.Region == "" {
+		defaults.Storage.Region = DefaultS3Region
+	}
+	return defaults
+}
+
+// DefaultFederationName returns the name of the control plane of --karmada-kubeconfig
+func (s *Settings) DefaultFederationName() string {
+	if s == nil || s.DefaultFederation == "" {
+		return defaultFederationName
+	}
+	return s.DefaultFederation
+}
+
+// FederationFor returns the federation jobs of a namespace are sent to when
+// they do not name one: the federation listing the namespace, else the default
+func (s *Settings) FederationFor(namespace string) string {
+	if s != nil {
+		for _, federation := range s.Federations {
+			for _, ns := range federation.Namespaces {
+				if ns == namespace {
+					return federation.Name
+				}
+			}
+		}
+	}
+	return s.DefaultFederationName()
+}
+
+// PriorityClassFor returns the priority mapping for a job priority, or nil if none applies
+func (s *Settings) PriorityClassFor(priority int) *PriorityClassMapping {
+	if s == nil || len(s.PriorityClasses) == 0 {
+		return nil
+	}
+
+	mappings := make([]PriorityClassMapping, len(s.PriorityClasses))
+	copy(mappings, s.PriorityClasses)
+	sort.Slice(mappings, func(i, j int) bool {
+		return mappings[i].MinPriority > mappings[j].MinPriority
+	})
+
+	for i := range mappings {
+		if priority >= mappings[i].MinPriority {
+			return &mappings[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,347 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// SettingsConfigMapKey is the ConfigMap key holding the settings, in the settings file format
+const SettingsConfigMapKey = "settings.yaml"
+
+// Keys of the settings Secret holding the object storage credentials
+const (
+	SecretAccessKey = "accessKey"
+	SecretSecretKey = "secretKey"
+	SecretRegion    = "region"
+)
+
+// Settings sources
+const (
+	SettingsSourceDefault   = "default"
+	SettingsSourceFile      = "file"
+	SettingsSourceConfigMap = "configmap"
+)
+
+// settingsResync is how often the settings informers replay the ConfigMap and Secret
+const settingsResync = 10 * time.Minute
+
+// redacted replaces credentials in settings shown to users
+const redacted = "REDACTED"
+
+// SettingsVersion describes the active platform settings
+type SettingsVersion struct {
+	// Version is a hash of the active settings, so equal settings have equal versions
+	Version   string    `json:"version"`
+	Source    string    `json:"source"`              // "default", "file" or "configmap"
+	File      string    `json:"file,omitempty"`      // Settings file path
+	ConfigMap string    `json:"configMap,omitempty"` // namespace/name@resourceVersion
+	Secret    string    `json:"secret,omitempty"`    // namespace/name@resourceVersion
+	LoadedAt  time.Time `json:"loadedAt"`
+	// Error is why the latest change was not applied; the settings of Version stay active
+	Error string `json:"error,omitempty"`
+}
+
+// CurrentSettings returns the active platform settings. They change when the
+// settings ConfigMap or Secret is updated, so read them once per operation.
+func (c *Config) CurrentSettings() *Settings {
+	c.settingsMu.RLock()
+	defer c.settingsMu.RUnlock()
+	if c.currentSettings != nil {
+		return c.currentSettings
+	}
+	return c.Settings
+}
+
+// SettingsVersion returns the version of the active platform settings
+func (c *Config) SettingsVersion() SettingsVersion {
+	c.settingsMu.RLock()
+	defer c.settingsMu.RUnlock()
+	return c.settingsVersion
+}
+
+// loadSettings reads the settings file or ConfigMap and the Secret at startup
+func (c *Config) loadSettings(ctx context.Context) error {
+	if c.SettingsFile != "" && c.SettingsConfigMap != "" {
+		return fmt.Errorf("use either a settings file or a settings ConfigMap, not both")
+	}
+
+	fileSettings, err := LoadSettings(c.SettingsFile)
+	if err != nil {
+		return err
+	}
+	c.fileSettings = fileSettings
+
+	var configMap *corev1.ConfigMap
+	if c.SettingsConfigMap != "" {
+		namespace, name, err := splitObjectRef(c.SettingsConfigMap)
+		if err != nil {
+			return err
+		}
+		configMap, err = c.MgmtClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get settings ConfigMap %s: %w", c.SettingsConfigMap, err)
+		}
+	}
+	var secret *corev1.Secret
+	if c.SettingsSecret != "" {
+		namespace, name, err := splitObjectRef(c.SettingsSecret)
+		if err != nil {
+			return err
+		}
+		secret, err = c.MgmtClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get settings Secret %s: %w", c.SettingsSecret, err)
+		}
+	}
+
+	settings, version, err := c.readSettings(configMap, secret, nil)
+	if err != nil {
+		return err
+	}
+	c.Settings = settings
+	c.settingsVersion = version
+	log.Printf("Platform settings %s loaded from %s", version.Version, version.Source)
+	return nil
+}
+
+// WatchSettings reloads the settings whenever the settings ConfigMap or Secret
+// changes, until ctx is done. Settings that fail to parse or validate are
+// logged and reported by SettingsVersion; the previous settings stay active.
+// Federations are only read at startup.
+func (c *Config) WatchSettings(ctx context.Context) error {
+	var synced []cache.InformerSynced
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { c.reloadSettings() },
+		UpdateFunc: func(interface{}, interface{}) { c.reloadSettings() },
+		DeleteFunc: func(interface{}) { c.reloadSettings() },
+	}
+
+	if c.SettingsConfigMap != "" {
+		factory, err := c.settingsInformerFactory(c.SettingsConfigMap)
+		if err != nil {
+			return err
+		}
+		informer := factory.Core().V1().ConfigMaps()
+		informer.Informer().AddEventHandler(handler)
+		c.configMapLister = informer.Lister()
+		synced = append(synced, informer.Informer().HasSynced)
+		factory.Start(ctx.Done())
+	}
+	if c.SettingsSecret != "" {
+		factory, err := c.settingsInformerFactory(c.SettingsSecret)
+		if err != nil {
+			return err
+		}
+		informer := factory.Core().V1().Secrets()
+		informer.Informer().AddEventHandler(handler)
+		c.secretLister = informer.Lister()
+		synced = append(synced, informer.Informer().HasSynced)
+		factory.Start(ctx.Done())
+	}
+	if len(synced) == 0 {
+		return nil
+	}
+
+	if !cache.WaitForCacheSync(ctx.Done(), synced...) {
+		return fmt.Errorf("failed to sync the settings ConfigMap and Secret")
+	}
+	c.settingsSynced.Store(true)
+	c.reloadSettings()
+	log.Println("Watching platform settings for changes")
+	return nil
+}
+
+// settingsInformerFactory creates an informer factory watching one object of the management cluster
+func (c *Config) settingsInformerFactory(ref string) (informers.SharedInformerFactory, error) {
+	namespace, name, err := splitObjectRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	return informers.NewSharedInformerFactoryWithOptions(c.MgmtClient, settingsResync,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}),
+	), nil
+}
+
+// reloadSettings applies the ConfigMap and Secret in the informer caches if they changed the settings
+func (c *Config) reloadSettings() {
+	if !c.settingsSynced.Load() {
+		// Events of the initial list; applied once all caches synced
+		return
+	}
+
+	settings, version, err := c.readWatchedSettings()
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	if err != nil {
+		if c.settingsVersion.Error != err.Error() {
+			log.Printf("Keeping platform settings %s: %v", c.settingsVersion.Version, err)
+		}
+		c.settingsVersion.Error = err.Error()
+		return
+	}
+	if version.Version == c.settingsVersion.Version {
+		c.settingsVersion.Error = ""
+		return
+	}
+
+	c.currentSettings = settings
+	c.settingsVersion = version
+	log.Printf("Platform settings %s loaded from %s", version.Version, version.Source)
+}
+
+// readWatchedSettings builds the settings from the informer caches
+func (c *Config) readWatchedSettings() (*Settings, SettingsVersion, error) {
+	var configMap *corev1.ConfigMap
+	if c.configMapLister != nil {
+		namespace, name, _ := splitObjectRef(c.SettingsConfigMap)
+		var err error
+		configMap, err = c.configMapLister.ConfigMaps(namespace).Get(name)
+		if err != nil {
+			return nil, SettingsVersion{}, fmt.Errorf("settings ConfigMap %s: %w", c.SettingsConfigMap, err)
+		}
+	}
+	var secret *corev1.Secret
+	if c.secretLister != nil {
+		namespace, name, _ := splitObjectRef(c.SettingsSecret)
+		var err error
+		secret, err = c.secretLister.Secrets(namespace).Get(name)
+		if err != nil {
+			return nil, SettingsVersion{}, fmt.Errorf("settings Secret %s: %w", c.SettingsSecret, err)
+		}
+	}
+	return c.readSettings(configMap, secret, c.Settings)
+}
+
+// readSettings builds the settings from the ConfigMap, or the settings file
+// without one, and the storage credentials of the Secret. With startup
+// settings, their federations are kept, since the Karmada clients are only
+// built at startup.
+func (c *Config) readSettings(configMap *corev1.ConfigMap, secret *corev1.Secret, startup *Settings) (*Settings, SettingsVersion, error) {
+	version := SettingsVersion{Source: SettingsSourceDefault, LoadedAt: time.Now()}
+
+	var settings *Settings
+	switch {
+	case configMap != nil:
+		data, ok := configMap.Data[SettingsConfigMapKey]
+		if !ok {
+			return nil, version, fmt.Errorf("settings ConfigMap %s has no %s key", c.SettingsConfigMap, SettingsConfigMapKey)
+		}
+		var err error
+		if settings, err = parseSettings([]byte(data)); err != nil {
+			return nil, version, fmt.Errorf("settings ConfigMap %s: %w", c.SettingsConfigMap, err)
+		}
+		version.Source = SettingsSourceConfigMap
+		version.ConfigMap = fmt.Sprintf("%s@%s", c.SettingsConfigMap, configMap.ResourceVersion)
+	case c.fileSettings != nil:
+		var err error
+		if settings, err = c.fileSettings.clone(); err != nil {
+			return nil, version, err
+		}
+		if c.SettingsFile != "" {
+			version.Source = SettingsSourceFile
+			version.File = c.SettingsFile
+		}
+	default:
+		settings = &Settings{}
+	}
+
+	if secret != nil {
+		applyStorageSecret(settings, secret)
+		version.Secret = fmt.Sprintf("%s@%s", c.SettingsSecret, secret.ResourceVersion)
+	}
+
+	if startup != nil && (startup.DefaultFederationName() != settings.DefaultFederationName() || !reflect.DeepEqual(startup.Federations, settings.Federations)) {
+		log.Println("Federation settings changed; they take effect after a restart")
+		settings.DefaultFederation = startup.DefaultFederation
+		settings.Federations = startup.Federations
+	}
+
+	if err := settings.Validate(); err != nil {
+		return nil, version, fmt.Errorf("invalid settings: %w", err)
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, version, fmt.Errorf("failed to encode settings: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	version.Version = hex.EncodeToString(sum[:])[:12]
+	return settings, version, nil
+}
+
+// applyStorageSecret sets the storage credentials of the settings from the Secret
+func applyStorageSecret(settings *Settings, secret *corev1.Secret) {
+	defaults := JobDefaults{}
+	if settings.Defaults != nil {
+		defaults = *settings.Defaults
+	}
+	for key, field := range map[string]*string{
+		SecretAccessKey: &defaults.Storage.AccessKey,
+		SecretSecretKey: &defaults.Storage.SecretKey,
+		SecretRegion:    &defaults.Storage.Region,
+	} {
+		if value, ok := secret.Data[key]; ok {
+			*field = string(value)
+		}
+	}
+	settings.Defaults = &defaults
+}
+
+// Redacted returns a copy of the settings without the storage credentials
+func (s *Settings) Redacted() (*Settings, error) {
+	settings, err := s.clone()
+	if err != nil {
+		return nil, err
+	}
+	if settings.Defaults != nil {
+		for _, field := range []*string{&settings.Defaults.Storage.AccessKey, &settings.Defaults.Storage.SecretKey} {
+			if *field != "" {
+				*field = redacted
+			}
+		}
+	}
+	return settings, nil
+}
+
+// clone deep-copies the settings
+func (s *Settings) clone() (*Settings, error) {
+	settings := &Settings{}
+	if s == nil {
+		return settings, nil
+	}
+	data, err := json.Marshal(s)
+	if err == nil {
+		err = json.Unmarshal(data, settings)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy settings: %w", err)
+	}
+	return settings, nil
+}
+
+// splitObjectRef splits a namespace/name reference; a bare name is in the default namespace
+func splitObjectRef(ref string) (namespace, name string, err error) {
+	namespace, name, err = cache.SplitMetaNamespaceKey(ref)
+	if err != nil || name == "" {
+		return "", "", fmt.Errorf("invalid object reference %q (expected namespace/name)", ref)
+	}
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	return namespace, name, nil
+}
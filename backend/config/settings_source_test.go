@@ -0,0 +1,132 @@
+package config
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+func settingsConfigMap(data string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ml-platform", Name: "platform-settings"},
+		Data:       map[string]string{SettingsConfigMapKey: data},
+	}
+}
+
+// waitForSettings polls until the active settings satisfy done
+func waitForSettings(t *testing.T, cfg *Config, done func(*Settings, SettingsVersion) bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if done(cfg.CurrentSettings(), cfg.SettingsVersion()) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("settings not reloaded; version %+v", cfg.SettingsVersion())
+}
+
+func TestSettingsFromConfigMapAndSecret(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		settingsConfigMap("defaults:\n  rayVersion: 2.40.0\n  images:\n    xgboost: registry.local/xgboost:1\n"),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ml-platform", Name: "platform-storage"},
+			Data:       map[string][]byte{SecretAccessKey: []byte("access"), SecretSecretKey: []byte("secret")},
+		},
+	)
+	cfg := &Config{
+		MgmtClient:        client,
+		SettingsConfigMap: "ml-platform/platform-settings",
+		SettingsSecret:    "ml-platform/platform-storage",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := cfg.loadSettings(ctx); err != nil {
+		t.Fatalf("loadSettings: %v", err)
+	}
+	defaults := cfg.CurrentSettings().JobDefaults()
+	if defaults.RayVersion != "2.40.0" || defaults.ImageFor("xgboost") != "registry.local/xgboost:1" || defaults.ImageFor("ray") != DefaultImage {
+		t.Errorf("defaults = %+v, want the ConfigMap's", defaults)
+	}
+	if defaults.Storage != (StorageCredentials{Region: DefaultS3Region, AccessKey: "access", SecretKey: "secret"}) {
+		t.Errorf("storage = %+v, want the Secret's credentials", defaults.Storage)
+	}
+	startup := cfg.SettingsVersion()
+	if startup.Source != SettingsSourceConfigMap || startup.Version == "" {
+		t.Errorf("version = %+v, want a configmap version", startup)
+	}
+
+	if err := cfg.WatchSettings(ctx); err != nil {
+		t.Fatalf("WatchSettings: %v", err)
+	}
+	if version := cfg.SettingsVersion(); version.Version != startup.Version {
+		t.Errorf("version after watch = %s, want unchanged %s", version.Version, startup.Version)
+	}
+
+	// Changes take effect without a restart
+	_, err := client.CoreV1().ConfigMaps("ml-platform").Update(ctx, settingsConfigMap("defaults:\n  pvcName: shared-results\n"), metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("update ConfigMap: %v", err)
+	}
+	waitForSettings(t, cfg, func(s *Settings, _ SettingsVersion) bool {
+		return s.JobDefaults().PVCName == "shared-results"
+	})
+	if defaults := cfg.CurrentSettings().JobDefaults(); defaults.RayVersion != DefaultRayVersion || defaults.Storage.SecretKey != "secret" {
+		t.Errorf("defaults after reload = %+v, want built-in Ray version and the Secret's credentials", defaults)
+	}
+	reloaded := cfg.SettingsVersion()
+
+	// Invalid settings are reported and the previous ones stay active
+	_, err = client.CoreV1().ConfigMaps("ml-platform").Update(ctx, settingsConfigMap("clusterSelection:\n  strategy: random\n"), metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("update ConfigMap: %v", err)
+	}
+	waitForSettings(t, cfg, func(_ *Settings, version SettingsVersion) bool {
+		return version.Error != ""
+	})
+	if version := cfg.SettingsVersion(); version.Version != reloaded.Version {
+		t.Errorf("version after invalid update = %s, want %s", version.Version, reloaded.Version)
+	}
+	if got := cfg.CurrentSettings().JobDefaults().PVCName; got != "shared-results" {
+		t.Errorf("PVC name after invalid update = %q, want shared-results", got)
+	}
+}
+
+func TestSettingsFileAndConfigMapExclusive(t *testing.T) {
+	cfg := &Config{
+		MgmtClient:        fake.NewSimpleClientset(),
+		SettingsFile:      "settings.yaml",
+		SettingsConfigMap: "ml-platform/platform-settings",
+	}
+	if err := cfg.loadSettings(context.Background()); err == nil {
+		t.Error("loadSettings succeeded with both a settings file and ConfigMap")
+	}
+}
+
+func TestRedactedSettings(t *testing.T) {
+	settings := &Settings{Defaults: &JobDefaults{Storage: StorageCredentials{AccessKey: "access", SecretKey: "secret", Region: "eu-west-1"}}}
+	redactedSettings, err := settings.Redacted()
+	if err != nil {
+		t.Fatalf("Redacted failed: %v", err)
+	}
+	storage := redactedSettings.Defaults.Storage
+	if storage.AccessKey != redacted || storage.SecretKey != redacted || storage.Region != "eu-west-1" {
+		t.Errorf("redacted storage = %+v", storage)
+	}
+	if settings.Defaults.Storage.SecretKey != "secret" {
+		t.Error("Redacted modified the settings")
+	}
+}
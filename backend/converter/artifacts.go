@@ -0,0 +1,92 @@
+package converter
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// Artifact URI schemes accepted in outputDataConfig.artifactUri
+const (
+	ArtifactSchemeFile    = "file"    // Path inside the pods, usually on the result-storage PVC
+	ArtifactSchemeStorage = "storage" // Platform result storage (the shared PVC)
+	ArtifactSchemeS3      = "s3"      // S3 or MinIO bucket and prefix
+)
+
+// ValidateOutputDataConfig rejects artifact URIs the converter cannot render
+func ValidateOutputDataConfig(out *models.OutputDataConfig, inputs []models.InputDataConfig) error {
+	if out.ArtifactURI == "" {
+		return nil
+	}
+
+	u, err := url.Parse(out.ArtifactURI)
+	if err != nil {
+		return fmt.Errorf("invalid outputDataConfig.artifactUri: %w", err)
+	}
+
+	switch u.Scheme {
+	case ArtifactSchemeFile:
+		if !strings.HasPrefix(strings.TrimPrefix(out.ArtifactURI, "file://"), "/") {
+			return fmt.Errorf("outputDataConfig.artifactUri must be an absolute file:// path")
+		}
+	case ArtifactSchemeStorage:
+	case ArtifactSchemeS3:
+		if u.Host == "" {
+			return fmt.Errorf("outputDataConfig.artifactUri must name a bucket (s3://bucket/prefix)")
+		}
+		if artifactEndpoint(out, inputs) == "" {
+			return fmt.Errorf("outputDataConfig.endpoint is required for s3:// artifact URIs")
+		}
+	default:
+		return fmt.Errorf("unsupported outputDataConfig.artifactUri scheme %q (expected file://, storage:// or s3://)", u.Scheme)
+	}
+
+	return nil
+}
+
+// isS3ArtifactURI reports whether artifacts are uploaded to object storage
+func isS3ArtifactURI(artifactURI string) bool {
+	return strings.HasPrefix(artifactURI, ArtifactSchemeS3+"://")
+}
+
+// artifactEndpoint returns the S3 endpoint for uploads, defaulting to the first input channel's
+func artifactEndpoint(out *models.OutputDataConfig, inputs []models.InputDataConfig) string {
+	if out.Endpoint != "" {
+		return out.Endpoint
+	}
+	if len(inputs) > 0 {
+		return inputs[0].Endpoint
+	}
+	return ""
+}
+
+// appendArtifactUploadEnv adds the upload destination for s3:// artifact URIs;
+// the credentials come from the job's storage Secret
+func (c *Converter) appendArtifactUploadEnv(sb *strings.Builder, req *models.TrainingJobRequest) {
+	if !isS3ArtifactURI(req.OutputDataConfig.ArtifactURI) {
+		return
+	}
+
+	endpoint := artifactEndpoint(&req.OutputDataConfig, req.InputDataConfig)
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+
+	sb.WriteString("\n  # ==== Artifact Upload ====\n")
+	sb.WriteString(fmt.Sprintf("  ARTIFACT_URI: %q\n", c.deriveStoragePath(req.OutputDataConfig.ArtifactURI)))
+	sb.WriteString(fmt.Sprintf("  AWS_ENDPOINT_URL: %q\n", endpoint))
+	sb.WriteString(fmt.Sprintf("  AWS_DEFAULT_REGION: \"%s\"\n", c.defaults.Storage.Region))
+}
+
+// ArtifactLocation returns where a finished job's artifacts end up: the storage
+// path followed by the run name, matching how the training scripts lay them out
+func ArtifactLocation(req *models.TrainingJobRequest) string {
+	storagePath := (&Converter{}).deriveStoragePath(req.OutputDataConfig.ArtifactURI)
+	if isS3ArtifactURI(storagePath) {
+		return storagePath + "/" + req.JobName
+	}
+	return "file://" + path.Join(storagePath, req.JobName)
+}
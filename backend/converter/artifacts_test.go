@@ -0,0 +1,86 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/loiht2/ml-platform-training-job/backend/config"
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+func TestValidateOutputDataConfig(t *testing.T) {
+	inputs := []models.InputDataConfig{{Endpoint: "minio.local:9000", Bucket: "data"}}
+	tests := []struct {
+		name    string
+		out     models.OutputDataConfig
+		inputs  []models.InputDataConfig
+		wantErr bool
+	}{
+		{"default", models.OutputDataConfig{}, nil, false},
+		{"absolute file path", models.OutputDataConfig{ArtifactURI: "file:///mnt/results"}, nil, false},
+		{"relative file path", models.OutputDataConfig{ArtifactURI: "file://results"}, nil, true},
+		{"platform storage", models.OutputDataConfig{ArtifactURI: "storage://churn"}, nil, false},
+		{"s3 with endpoint", models.OutputDataConfig{ArtifactURI: "s3://models/churn", Endpoint: "minio.local:9000"}, nil, false},
+		{"s3 with input endpoint", models.OutputDataConfig{ArtifactURI: "s3://models/churn"}, inputs, false},
+		{"s3 without endpoint", models.OutputDataConfig{ArtifactURI: "s3://models/churn"}, nil, true},
+		{"s3 without bucket", models.OutputDataConfig{ArtifactURI: "s3:///churn", Endpoint: "minio.local:9000"}, nil, true},
+		{"unsupported scheme", models.OutputDataConfig{ArtifactURI: "gs://models/churn"}, nil, true},
+		{"unparsable", models.OutputDataConfig{ArtifactURI: "s3://models/%zz"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateOutputDataConfig(&tt.out, tt.inputs); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateOutputDataConfig() error = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestArtifactLocation(t *testing.T) {
+	tests := []struct {
+		artifactURI string
+		want        string
+	}{
+		{"", "file://" + DefaultStoragePath + "/churn"},
+		{"storage://anything", "file://" + DefaultStoragePath + "/churn"},
+		{"file:///mnt/results/", "file:///mnt/results/churn"},
+		{"s3://models/runs/", "s3://models/runs/churn"},
+	}
+
+	for _, tt := range tests {
+		req := &models.TrainingJobRequest{JobName: "churn", OutputDataConfig: models.OutputDataConfig{ArtifactURI: tt.artifactURI}}
+		if got := ArtifactLocation(req); got != tt.want {
+			t.Errorf("ArtifactLocation(%q) = %q, want %q", tt.artifactURI, got, tt.want)
+		}
+	}
+}
+
+func TestAppendArtifactUploadEnv(t *testing.T) {
+	c := NewConverter(&config.Settings{Defaults: &config.JobDefaults{
+		Storage: config.StorageCredentials{AccessKey: "key", SecretKey: "secret", Region: "eu-west-1"},
+	}})
+
+	var sb strings.Builder
+	c.appendArtifactUploadEnv(&sb, &models.TrainingJobRequest{OutputDataConfig: models.OutputDataConfig{ArtifactURI: "file:///mnt/results"}})
+	if sb.Len() != 0 {
+		t.Errorf("file:// artifacts got upload env:\n%s", sb.String())
+	}
+
+	c.appendArtifactUploadEnv(&sb, &models.TrainingJobRequest{
+		InputDataConfig:  []models.InputDataConfig{{Endpoint: "minio.local:9000"}},
+		OutputDataConfig: models.OutputDataConfig{ArtifactURI: "s3://models/runs/"},
+	})
+	for _, line := range []string{
+		`ARTIFACT_URI: "s3://models/runs"`,
+		`AWS_ENDPOINT_URL: "https://minio.local:9000"`,
+		`AWS_DEFAULT_REGION: "eu-west-1"`,
+	} {
+		if !strings.Contains(sb.String(), line) {
+			t.Errorf("upload env does not contain %s:\n%s", line, sb.String())
+		}
+	}
+	if strings.Contains(sb.String(), "secret") {
+		t.Errorf("upload env carries the secret key:\n%s", sb.String())
+	}
+}
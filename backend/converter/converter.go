@@ -8,63 +8,133 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/loiht2/ml-platform-training-job/backend/config"
 	"github.com/loiht2/ml-platform-training-job/backend/models"
 )
 
 const (
-	DefaultRayVersion      = "2.46.0"
-	DefaultHeadImage       = "kiepdoden123/iris-training-ray:v1.2"
-	DefaultWorkerImage     = "kiepdoden123/iris-training-ray:v1.2"
-	DefaultEntrypoint      = "python /home/ray/xgboost_train.py"
-	DefaultStoragePath     = "/home/ray/result-storage"
-	DefaultLabelColumn     = "target"
-	DefaultS3Region        = "us-east-1"
-	DefaultS3AccessKey     = "loiht2"
-	DefaultS3SecretKey     = "E4XWyvYtlS6E9Q92DPq7sJBoJhaa1j7pbLHhgfeZ"
-	DefaultPVCName         = "kham-pv-for-xgboost"
-	DefaultMountPath       = "/home/ray/result-storage"
+	DefaultEntrypoint  = "python /home/ray/xgboost_train.py"
+	DefaultStoragePath = "/home/ray/result-storage"
+	DefaultLabelColumn = "target"
+	DefaultMountPath   = "/home/ray/result-storage"
 )
 
 // Converter handles conversion from frontend models to K8s resources
-type Converter struct{}
+type Converter struct {
+	settings *config.Settings
+	defaults config.JobDefaults
+}
 
 // NewConverter creates a new converter instance
-func NewConverter() *Converter {
-	return &Converter{}
+func NewConverter(settings *config.Settings) *Converter {
+	return &Converter{settings: settings, defaults: settings.JobDefaults()}
 }
 
-// ConvertToRayJobV2 converts the new TrainingJobRequest format to RayJob
+// ConvertToRayJobV2 converts the new TrainingJobRequest format to RayJob,
+// queued as its target clusters are set up for
 func (c *Converter) ConvertToRayJobV2(req *models.TrainingJobRequest, jobID string) (map[string]interface{}, error) {
+	queueing, err := c.settings.QueueingFor(req.TargetClusters)
+	if err != nil {
+		return nil, err
+	}
+	return c.ConvertToRayJobWithQueueing(req, jobID, queueing)
+}
+
+// ConvertToRayJobWithQueueing converts a TrainingJobRequest to a RayJob queued
+// (Kueue) or gang scheduled (Volcano) with the given integration, or neither
+// when it is nil. Jobs placed on a picked cluster use that cluster's.
+func (c *Converter) ConvertToRayJobWithQueueing(req *models.TrainingJobRequest, jobID string, queueing *config.QueueingSettings) (map[string]interface{}, error) {
 	namespace := req.Namespace
 	if namespace == "" {
 		namespace = "default"
 	}
 
+	custom := IsCustomAlgorithm(req)
+
 	// Determine entrypoint
 	entrypoint := req.Entrypoint
-	if entrypoint == "" {
+	if custom {
+		entrypoint = customEntrypoint(&req.Algorithm)
+	} else if entrypoint == "" {
 		entrypoint = DefaultEntrypoint
 	}
 
 	// Determine images
+	defaultImage := c.defaults.ImageFor(req.Algorithm.AlgorithmName)
+	defaultHeadImage, defaultWorkerImage := defaultImage, defaultImage
+	if custom {
+		defaultHeadImage, defaultWorkerImage = c.defaults.CustomImage, c.defaults.CustomImage
+		if req.Algorithm.Image != "" {
+			defaultHeadImage, defaultWorkerImage = req.Algorithm.Image, req.Algorithm.Image
+		}
+	}
 	headImage := req.HeadImage
 	if headImage == "" {
-		headImage = DefaultHeadImage
+		headImage = defaultHeadImage
 	}
 	workerImage := req.WorkerImage
 	if workerImage == "" {
-		workerImage = DefaultWorkerImage
+		workerImage = defaultWorkerImage
 	}
 
 	// Determine PVC name
-	pvcName := req.PVCName
-	if pvcName == "" {
-		pvcName = DefaultPVCName
-	}
+	pvcName := c.ClaimName(req)
 
 	// Build runtime environment YAML
 	runtimeEnvYAML := c.buildRuntimeEnvYAML(req)
 
+	headGroupSpec := c.buildRayHeadGroupSpecV2(req, headImage, pvcName)
+	workerGroupSpec := c.buildRayWorkerGroupSpecV2(req, workerImage, pvcName)
+
+	// Shared memory, scratch space and data staging
+	c.applyPodVolumes(headGroupSpec, req)
+	c.applyPodVolumes(workerGroupSpec, req)
+
+	// Storage credentials come from the job's Secret, not the runtime env
+	c.applyStorageEnv(headGroupSpec, req)
+	c.applyStorageEnv(workerGroupSpec, req)
+
+	// Map the job priority to a PriorityClass on head and worker pods
+	if mapping := c.settings.PriorityClassFor(req.Priority); mapping != nil && mapping.PriorityClassName != "" {
+		podSpec(headGroupSpec)["priorityClassName"] = mapping.PriorityClassName
+		podSpec(workerGroupSpec)["priorityClassName"] = mapping.PriorityClassName
+	}
+
+	// Custom algorithms get their code and hyperparameters file through init containers
+	if custom {
+		hyperparametersJSON, err := c.buildHyperparametersJSON(req)
+		if err != nil {
+			return nil, err
+		}
+		c.applyCustomCode(headGroupSpec, req, hyperparametersJSON)
+		c.applyCustomCode(workerGroupSpec, req, hyperparametersJSON)
+	}
+
+	labels := map[string]string{
+		"app":             req.JobName,
+		"training-job-id": jobID,
+		"algorithm":       req.Algorithm.AlgorithmName,
+	}
+	// Label the pods too, so they can be found by job in the member clusters
+	labelPodTemplates(jobID, headGroupSpec, workerGroupSpec)
+
+	spec := map[string]interface{}{
+		"entrypoint":     entrypoint,
+		"runtimeEnvYAML": runtimeEnvYAML,
+		"rayClusterSpec": map[string]interface{}{
+			"rayVersion":    c.defaults.RayVersion,
+			"headGroupSpec": headGroupSpec,
+			"workerGroupSpecs": []interface{}{
+				workerGroupSpec,
+			},
+		},
+	}
+
+	// Queue the job (Kueue) or gang schedule it (Volcano) if its clusters are set up for it
+	if err := c.applyQueueing(spec, labels, queueing, namespace, headGroupSpec, workerGroupSpec); err != nil {
+		return nil, err
+	}
+
 	// Build Ray cluster spec
 	rayJob := map[string]interface{}{
 		"apiVersion": "ray.io/v1",
@@ -72,26 +142,12 @@ func (c *Converter) ConvertToRayJobV2(req *models.TrainingJobRequest, jobID stri
 		"metadata": map[string]interface{}{
 			"name":      req.JobName,
 			"namespace": namespace,
-			"labels": map[string]string{
-				"app":             req.JobName,
-				"training-job-id": jobID,
-				"algorithm":       req.Algorithm.AlgorithmName,
-			},
+			"labels":    labels,
 			"annotations": map[string]string{
 				"training-job-id": jobID,
 			},
 		},
-		"spec": map[string]interface{}{
-			"entrypoint":       entrypoint,
-			"runtimeEnvYAML":   runtimeEnvYAML,
-			"rayClusterSpec": map[string]interface{}{
-				"rayVersion":      DefaultRayVersion,
-				"headGroupSpec":   c.buildRayHeadGroupSpecV2(req, headImage, pvcName),
-				"workerGroupSpecs": []interface{}{
-					c.buildRayWorkerGroupSpecV2(req, workerImage, pvcName),
-				},
-			},
-		},
+		"spec": spec,
 	}
 
 	return rayJob, nil
@@ -100,53 +156,69 @@ func (c *Converter) ConvertToRayJobV2(req *models.TrainingJobRequest, jobID stri
 // buildRuntimeEnvYAML creates the runtime environment YAML with all environment variables
 func (c *Converter) buildRuntimeEnvYAML(req *models.TrainingJobRequest) string {
 	var sb strings.Builder
-	
+
 	sb.WriteString("env_vars:\n")
 	sb.WriteString("  # ==== TRAINING CONTROL ====\n")
-	
+
 	// NUM_WORKER
 	sb.WriteString(fmt.Sprintf("  NUM_WORKER: \"%d\"\n", req.Resources.InstanceCount))
-	
+
 	// USE_GPU
 	useGPU := "false"
 	if req.Resources.InstanceResources.GPUCount > 0 {
 		useGPU = "true"
 	}
 	sb.WriteString(fmt.Sprintf("  USE_GPU: \"%s\"\n", useGPU))
-	
+
 	// LABEL_COLUMN
 	sb.WriteString(fmt.Sprintf("  LABEL_COLUMN: \"%s\"\n", DefaultLabelColumn))
-	
+
 	// RUN_NAME
 	sb.WriteString(fmt.Sprintf("  RUN_NAME: \"%s\"\n", req.JobName))
-	
+
 	// STORAGE_PATH
 	storagePath := c.deriveStoragePath(req.OutputDataConfig.ArtifactURI)
 	sb.WriteString(fmt.Sprintf("  STORAGE_PATH: \"%s\"\n", storagePath))
-	
+
+	// Object storage upload destination and credentials
+	c.appendArtifactUploadEnv(&sb, req)
+
 	// S3/MinIO configuration
 	if len(req.InputDataConfig) > 0 {
 		inputConfig := req.InputDataConfig[0]
 		sb.WriteString("\n  # ==== S3/MinIO Configuration ====\n")
 		sb.WriteString(fmt.Sprintf("  S3_ENDPOINT: \"%s\"\n", inputConfig.Endpoint))
-		sb.WriteString(fmt.Sprintf("  S3_ACCESS_KEY: \"%s\"\n", DefaultS3AccessKey))
-		sb.WriteString(fmt.Sprintf("  S3_SECRET_KEY: \"%s\"\n", DefaultS3SecretKey))
-		sb.WriteString(fmt.Sprintf("  S3_REGION: \"%s\"\n", DefaultS3Region))
+		sb.WriteString(fmt.Sprintf("  S3_REGION: \"%s\"\n", c.defaults.Storage.Region))
 		sb.WriteString(fmt.Sprintf("  S3_BUCKET: \"%s\"\n", inputConfig.Bucket))
 		sb.WriteString(fmt.Sprintf("  S3_TRAIN_KEY: \"%s\"\n", inputConfig.Prefix))
-		
+
 		// If there's a second channel for validation
 		if len(req.InputDataConfig) > 1 {
 			sb.WriteString(fmt.Sprintf("  S3_VAL_KEY: \"%s\"\n", req.InputDataConfig[1].Prefix))
 		}
 	}
-	
+
+	// Local copies of the input channels, if staged
+	c.appendStagedDataEnv(&sb, req)
+
+	// Custom code reads its hyperparameters from a JSON file instead of env vars
+	if IsCustomAlgorithm(req) {
+		sb.WriteString("\n  # ==== Custom Code ====\n")
+		if req.Algorithm.Code != nil {
+			workDir := customWorkingDir(&req.Algorithm)
+			sb.WriteString(fmt.Sprintf("  CODE_DIR: \"%s\"\n", workDir))
+			sb.WriteString(fmt.Sprintf("  PYTHONPATH: \"%s\"\n", workDir))
+		}
+		sb.WriteString(fmt.Sprintf("  HYPERPARAMETERS_FILE: \"%s\"\n", HyperparametersFile))
+		return sb.String()
+	}
+
 	// XGBoost hyperparameters
 	if req.Hyperparameters.XGBoost != nil {
 		sb.WriteString("\n  # ==== XGBoost Hyperparameters ====\n")
 		c.appendXGBoostHyperparameters(&sb, req.Hyperparameters.XGBoost)
 	}
-	
+
 	// Custom hyperparameters
 	if len(req.CustomHyperparameters) > 0 {
 		sb.WriteString("\n  # ==== Custom Hyperparameters ====\n")
@@ -154,7 +226,7 @@ func (c *Converter) buildRuntimeEnvYAML(req *models.TrainingJobRequest) string {
 			sb.WriteString(fmt.Sprintf("  %s: \"%v\"\n", strings.ToUpper(key), value))
 		}
 	}
-	
+
 	return sb.String()
 }
 
@@ -162,21 +234,21 @@ func (c *Converter) buildRuntimeEnvYAML(req *models.TrainingJobRequest) string {
 func (c *Converter) appendXGBoostHyperparameters(sb *strings.Builder, xgb *models.XGBoostHyperparameters) {
 	// NUM_BOOST_ROUND
 	sb.WriteString(fmt.Sprintf("  NUM_BOOST_ROUND: \"%d\"\n", xgb.NumRound))
-	
+
 	// EARLY_STOPPING_ROUNDS
 	if xgb.EarlyStoppingRounds != nil {
 		sb.WriteString(fmt.Sprintf("  EARLY_STOPPING_ROUNDS: \"%d\"\n", *xgb.EarlyStoppingRounds))
 	} else {
 		sb.WriteString("  EARLY_STOPPING_ROUNDS: \"\"\n")
 	}
-	
+
 	// CSV_WEIGHT
 	sb.WriteString(fmt.Sprintf("  CSV_WEIGHT: \"%d\"\n", xgb.CSVWeights))
-	
+
 	// Basic parameters
 	sb.WriteString(fmt.Sprintf("  BOOSTER: \"%s\"\n", xgb.Booster))
 	sb.WriteString(fmt.Sprintf("  VERBOSITY: \"%d\"\n", xgb.Verbosity))
-	
+
 	// Learning parameters
 	sb.WriteString(fmt.Sprintf("  ETA: \"%.10g\"\n", xgb.Eta))
 	sb.WriteString(fmt.Sprintf("  GAMMA: \"%.10g\"\n", xgb.Gamma))
@@ -193,12 +265,12 @@ func (c *Converter) appendXGBoostHyperparameters(sb *strings.Builder, xgb *model
 	sb.WriteString(fmt.Sprintf("  TREE_METHOD: \"%s\"\n", xgb.TreeMethod))
 	sb.WriteString(fmt.Sprintf("  SKETCH_EPS: \"%.10g\"\n", xgb.SketchEps))
 	sb.WriteString(fmt.Sprintf("  SCALE_POS_WEIGHT: \"%.10g\"\n", xgb.ScalePosWeight))
-	
+
 	// Updater (only if not "auto" to keep it clean)
 	if xgb.Updater != "" && xgb.Updater != "auto" {
 		sb.WriteString(fmt.Sprintf("  UPDATER: \"%s\"\n", xgb.Updater))
 	}
-	
+
 	// Advanced parameters
 	sb.WriteString(fmt.Sprintf("  DSPLIT: \"%s\"\n", xgb.Dsplit))
 	sb.WriteString(fmt.Sprintf("  REFRESH_LEAF: \"%d\"\n", xgb.RefreshLeaf))
@@ -214,11 +286,11 @@ func (c *Converter) appendXGBoostHyperparameters(sb *strings.Builder, xgb *model
 	sb.WriteString(fmt.Sprintf("  SKIP_DROP: \"%.10g\"\n", xgb.SkipDrop))
 	sb.WriteString(fmt.Sprintf("  LAMBDA_BIAS: \"%.10g\"\n", xgb.LambdaBias))
 	sb.WriteString(fmt.Sprintf("  TWEEDIE_VARIANCE_POWER: \"%.10g\"\n", xgb.TweedieVariancePower))
-	
+
 	// Objective and metrics
 	sb.WriteString(fmt.Sprintf("  OBJECTIVE: \"%s\"\n", xgb.Objective))
 	sb.WriteString(fmt.Sprintf("  BASE_SCORE: \"%.10g\"\n", xgb.BaseScore))
-	
+
 	// EVAL_METRIC - join array with commas
 	if len(xgb.EvalMetric) > 0 {
 		sb.WriteString(fmt.Sprintf("  EVAL_METRIC: \"%s\"\n", strings.Join(xgb.EvalMetric, ",")))
@@ -231,6 +303,10 @@ func (c *Converter) deriveStoragePath(artifactURI string) string {
 	if strings.HasPrefix(artifactURI, "file://") {
 		return strings.TrimPrefix(artifactURI, "file://")
 	}
+	// s3:// URIs are handed to the training script as-is, without a trailing slash
+	if isS3ArtifactURI(artifactURI) {
+		return strings.TrimSuffix(artifactURI, "/")
+	}
 	// Otherwise use default
 	return DefaultStoragePath
 }
@@ -240,7 +316,7 @@ func (c *Converter) buildRayHeadGroupSpecV2(req *models.TrainingJobRequest, imag
 	// Build resource requirements
 	cpuStr := fmt.Sprintf("%d", req.Resources.InstanceResources.CPUCores)
 	memoryStr := fmt.Sprintf("%dGi", req.Resources.InstanceResources.MemoryGiB)
-	
+
 	resources := map[string]interface{}{
 		"limits": map[string]string{
 			"cpu": cpuStr,
@@ -249,13 +325,13 @@ func (c *Converter) buildRayHeadGroupSpecV2(req *models.TrainingJobRequest, imag
 			"cpu": cpuStr,
 		},
 	}
-	
+
 	// Add memory if specified
 	if req.Resources.InstanceResources.MemoryGiB > 0 {
 		resources["limits"].(map[string]string)["memory"] = memoryStr
 		resources["requests"].(map[string]string)["memory"] = memoryStr
 	}
-	
+
 	// Build container
 	container := map[string]interface{}{
 		"name":  "ray-head",
@@ -282,7 +358,7 @@ func (c *Converter) buildRayHeadGroupSpecV2(req *models.TrainingJobRequest, imag
 			},
 		},
 	}
-	
+
 	return map[string]interface{}{
 		"rayStartParams": map[string]string{},
 		"template": map[string]interface{}{
@@ -312,16 +388,16 @@ func (c *Converter) buildRayWorkerGroupSpecV2(req *models.TrainingJobRequest, im
 	if replicas == 0 {
 		replicas = 1
 	}
-	
+
 	maxReplicas := replicas * 5
 	if maxReplicas < 5 {
 		maxReplicas = 5
 	}
-	
+
 	// Build resource requirements
 	cpuStr := fmt.Sprintf("%d", req.Resources.InstanceResources.CPUCores)
 	memoryStr := fmt.Sprintf("%dGi", req.Resources.InstanceResources.MemoryGiB)
-	
+
 	resources := map[string]interface{}{
 		"limits": map[string]string{
 			"cpu": cpuStr,
@@ -330,20 +406,20 @@ func (c *Converter) buildRayWorkerGroupSpecV2(req *models.TrainingJobRequest, im
 			"cpu": cpuStr,
 		},
 	}
-	
+
 	// Add memory
 	if req.Resources.InstanceResources.MemoryGiB > 0 {
 		resources["limits"].(map[string]string)["memory"] = memoryStr
 		resources["requests"].(map[string]string)["memory"] = memoryStr
 	}
-	
+
 	// Add GPU if specified
 	if req.Resources.InstanceResources.GPUCount > 0 {
 		gpuStr := fmt.Sprintf("%d", req.Resources.InstanceResources.GPUCount)
 		resources["limits"].(map[string]string)["nvidia.com/gpu"] = gpuStr
 		resources["requests"].(map[string]string)["nvidia.com/gpu"] = gpuStr
 	}
-	
+
 	// Build container
 	container := map[string]interface{}{
 		"name":      "ray-worker",
@@ -356,7 +432,7 @@ func (c *Converter) buildRayWorkerGroupSpecV2(req *models.TrainingJobRequest, im
 			},
 		},
 	}
-	
+
 	return map[string]interface{}{
 		"replicas":       replicas,
 		"minReplicas":    1,
@@ -384,20 +460,98 @@ func (c *Converter) buildRayWorkerGroupSpecV2(req *models.TrainingJobRequest, im
 	}
 }
 
+// ResourceDemand returns the resources a job requests in a cluster: the head
+// pod plus every worker replica, each with the instance resources. Only the
+// workers request GPUs. The memory-backed /dev/shm that applyPodVolumes adds
+// to both groups counts against each pod's memory.
+func (c *Converter) ResourceDemand(req *models.TrainingJobRequest) models.ResourceAmounts {
+	workers := int64(req.Resources.InstanceCount)
+	if workers == 0 {
+		workers = 1
+	}
+	instance := req.Resources.InstanceResources
+	podMemoryGiB := int64(instance.MemoryGiB)
+	if req.Resources.SharedMemoryGiB > 0 {
+		podMemoryGiB += int64(req.Resources.SharedMemoryGiB)
+	}
+	return models.ResourceAmounts{
+		CPUCores:  float64(int64(instance.CPUCores) * (workers + 1)),
+		MemoryGiB: float64(podMemoryGiB * (workers + 1)),
+		GPUs:      int64(instance.GPUCount) * workers,
+		Pods:      workers + 1,
+	}
+}
+
+// labelPodTemplates adds the training-job-id label to the pod templates of
+// Ray groups, keeping the labels they already have
+func labelPodTemplates(jobID string, groupSpecs ...map[string]interface{}) {
+	for _, group := range groupSpecs {
+		template, _ := group["template"].(map[string]interface{})
+		if template == nil {
+			continue
+		}
+		metadata, _ := template["metadata"].(map[string]interface{})
+		if metadata == nil {
+			metadata = map[string]interface{}{}
+			template["metadata"] = metadata
+		}
+		labels, _ := metadata["labels"].(map[string]interface{})
+		if labels == nil {
+			labels = map[string]interface{}{}
+			metadata["labels"] = labels
+		}
+		labels["training-job-id"] = jobID
+	}
+}
+
+// OwnsPVC reports whether the job gets its own PVC rather than mounting an existing one
+func (c *Converter) OwnsPVC(req *models.TrainingJobRequest) bool {
+	return req.Resources.VolumeSizeGB > 0 && req.PVCName == ""
+}
+
+// ClaimName returns the PVC mounted as result storage: the requested claim,
+// the job's own claim, or the shared default claim
+func (c *Converter) ClaimName(req *models.TrainingJobRequest) string {
+	switch {
+	case req.PVCName != "":
+		return req.PVCName
+	case c.OwnsPVC(req):
+		return fmt.Sprintf("%s-pvc", req.JobName)
+	default:
+		return c.defaults.PVCName
+	}
+}
+
+// ValidateVolume checks the PVC options of a request
+func ValidateVolume(res *models.Resources) error {
+	if res.VolumeSizeGB < 0 {
+		return fmt.Errorf("resources.volumeSizeGB must not be negative")
+	}
+	switch corev1.PersistentVolumeAccessMode(res.VolumeAccessMode) {
+	case "", corev1.ReadWriteOnce, corev1.ReadOnlyMany, corev1.ReadWriteMany, corev1.ReadWriteOncePod:
+		return nil
+	default:
+		return fmt.Errorf("unsupported resources.volumeAccessMode %q", res.VolumeAccessMode)
+	}
+}
+
 // CreatePVC creates a PersistentVolumeClaim for the training job
 func (c *Converter) CreatePVC(req *models.TrainingJobRequest, jobID string) *corev1.PersistentVolumeClaim {
 	namespace := req.Namespace
 	if namespace == "" {
 		namespace = "default"
 	}
-	
-	pvcName := req.PVCName
-	if pvcName == "" {
-		pvcName = fmt.Sprintf("%s-pvc", req.JobName)
-	}
-	
+
+	pvcName := c.ClaimName(req)
+
 	storageSize := fmt.Sprintf("%dGi", req.Resources.VolumeSizeGB)
-	
+
+	// Head and workers share the claim, so it defaults to ReadWriteMany
+	accessMode := corev1.ReadWriteMany
+	if req.Resources.VolumeAccessMode != "" {
+		accessMode = corev1.PersistentVolumeAccessMode(req.Resources.VolumeAccessMode)
+	}
+
 	pvc := &corev1.PersistentVolumeClaim{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "v1",
@@ -413,7 +567,7 @@ func (c *Converter) CreatePVC(req *models.TrainingJobRequest, jobID string) *cor
 		},
 		Spec: corev1.PersistentVolumeClaimSpec{
 			AccessModes: []corev1.PersistentVolumeAccessMode{
-				corev1.ReadWriteMany,
+				accessMode,
 			},
 			Resources: corev1.ResourceRequirements{
 				Requests: corev1.ResourceList{
@@ -422,6 +576,11 @@ func (c *Converter) CreatePVC(req *models.TrainingJobRequest, jobID string) *cor
 			},
 		},
 	}
-	
+
+	if req.Resources.StorageClassName != "" {
+		storageClassName := req.Resources.StorageClassName
+		pvc.Spec.StorageClassName = &storageClassName
+	}
+
 	return pvc
 }
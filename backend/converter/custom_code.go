@@ -0,0 +1,309 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/loiht2/ml-platform-training-job/backend/config"
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+const (
+	AlgorithmSourceBuiltin = "builtin"
+	AlgorithmSourceCustom  = "custom"
+
+	DefaultGitImage         = "alpine/git:2.43.0"
+	DefaultMinioClientImage = "minio/mc:RELEASE.2024-01-13T07-53-03Z"
+	DefaultUtilityImage     = "busybox:1.36"
+	DefaultInlineFileName   = "main.py"
+
+	// MaxInlineScriptBytes keeps inline scripts within what a ConfigMap can hold
+	MaxInlineScriptBytes = 1 << 20
+
+	WorkspaceVolumeName  = "workspace"
+	InlineCodeVolumeName = "inline-code"
+	WorkspaceMountPath   = "/home/ray/workspace"
+	CodeDir              = WorkspaceMountPath + "/code"
+	HyperparametersFile  = WorkspaceMountPath + "/hyperparameters.json"
+
+	codeArchivePath     = WorkspaceMountPath + "/code.archive"
+	inlineCodeMountPath = "/home/ray/inline-code"
+	inlineCodeScriptKey = "script"
+)
+
+// IsCustomAlgorithm reports whether the request brings its own code
+func IsCustomAlgorithm(req *models.TrainingJobRequest) bool {
+	return req.Algorithm.Source == AlgorithmSourceCustom
+}
+
+// ValidateCustomAlgorithm checks the image, entrypoint and code source of a custom algorithm
+func ValidateCustomAlgorithm(alg *models.Algorithm) error {
+	if alg.Entrypoint == "" {
+		return fmt.Errorf("algorithm.entrypoint is required for custom algorithms")
+	}
+	if alg.Code == nil {
+		return nil
+	}
+
+	sources := 0
+	if git := alg.Code.Git; git != nil {
+		sources++
+		if git.Repository == "" {
+			return fmt.Errorf("algorithm.code.git.repository is required")
+		}
+		if strings.HasPrefix(git.Path, "/") || strings.Contains(git.Path, "..") {
+			return fmt.Errorf("algorithm.code.git.path must be a relative path inside the repository")
+		}
+	}
+	if s3 := alg.Code.S3; s3 != nil {
+		sources++
+		if s3.Endpoint == "" || s3.Bucket == "" || s3.Key == "" {
+			return fmt.Errorf("algorithm.code.s3 requires endpoint, bucket and key")
+		}
+		if _, err := minioHostURL(s3.Endpoint, config.StorageCredentials{}); err != nil {
+			return err
+		}
+	}
+	if inline := alg.Code.Inline; inline != nil {
+		sources++
+		if inline.Script == "" {
+			return fmt.Errorf("algorithm.code.inline.script is required")
+		}
+		if len(inline.Script) > MaxInlineScriptBytes {
+			return fmt.Errorf("algorithm.code.inline.script exceeds %d bytes", MaxInlineScriptBytes)
+		}
+		if inline.FileName != "" && (inline.FileName != path.Base(inline.FileName) || inline.FileName == "..") {
+			return fmt.Errorf("algorithm.code.inline.fileName must be a plain file name")
+		}
+	}
+	if sources > 1 {
+		return fmt.Errorf("only one of algorithm.code.git, algorithm.code.s3 and algorithm.code.inline may be set")
+	}
+	return nil
+}
+
+// customWorkingDir returns the directory the custom entrypoint runs from
+func customWorkingDir(alg *models.Algorithm) string {
+	if alg.Code != nil && alg.Code.Git != nil && alg.Code.Git.Path != "" {
+		return path.Join(CodeDir, alg.Code.Git.Path)
+	}
+	return CodeDir
+}
+
+// customEntrypoint wraps the user entrypoint so it runs from the code
+// directory. Without a code source the code comes with the image, so the
+// entrypoint runs as given.
+func customEntrypoint(alg *models.Algorithm) string {
+	if alg.Code == nil {
+		return alg.Entrypoint
+	}
+	return fmt.Sprintf("cd %s && %s", customWorkingDir(alg), alg.Entrypoint)
+}
+
+// inlineFileName returns the file an inline script is written to
+func inlineFileName(inline *models.InlineCodeSource) string {
+	if inline.FileName == "" {
+		return DefaultInlineFileName
+	}
+	return inline.FileName
+}
+
+// CodeConfigMapName returns the name of the ConfigMap holding a job's inline script
+func CodeConfigMapName(jobName string) string {
+	return fmt.Sprintf("%s-code", jobName)
+}
+
+// CreateCodeConfigMap creates the ConfigMap holding the inline script of a
+// custom algorithm, propagated together with the RayJob. Jobs without an
+// inline script get none.
+func (c *Converter) CreateCodeConfigMap(req *models.TrainingJobRequest, jobID string) *corev1.ConfigMap {
+	if !IsCustomAlgorithm(req) || req.Algorithm.Code == nil || req.Algorithm.Code.Inline == nil {
+		return nil
+	}
+	namespace := req.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CodeConfigMapName(req.JobName),
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app":             req.JobName,
+				"training-job-id": jobID,
+			},
+		},
+		Data: map[string]string{
+			inlineCodeScriptKey: req.Algorithm.Code.Inline.Script,
+		},
+	}
+}
+
+// buildHyperparametersJSON merges the typed and custom hyperparameters into one JSON document
+func (c *Converter) buildHyperparametersJSON(req *models.TrainingJobRequest) (string, error) {
+	params := map[string]interface{}{}
+	if req.Hyperparameters.XGBoost != nil {
+		data, err := json.Marshal(req.Hyperparameters.XGBoost)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal XGBoost hyperparameters: %w", err)
+		}
+		if err := json.Unmarshal(data, &params); err != nil {
+			return "", fmt.Errorf("failed to flatten XGBoost hyperparameters: %w", err)
+		}
+	}
+	for key, value := range req.CustomHyperparameters {
+		params[key] = value
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal hyperparameters: %w", err)
+	}
+	return string(data), nil
+}
+
+// applyCustomCode mounts a shared workspace into the group and fills it with
+// the training code and the hyperparameters file before Ray starts. Inline
+// scripts are mounted from the job's code ConfigMap.
+func (c *Converter) applyCustomCode(groupSpec map[string]interface{}, req *models.TrainingJobRequest, hyperparametersJSON string) {
+	alg := &req.Algorithm
+	mount := volumeMount(WorkspaceVolumeName, WorkspaceMountPath)
+	appendVolumes(groupSpec, emptyDirVolume(WorkspaceVolumeName, "", ""))
+	appendVolumeMounts(groupSpec, mount)
+	if alg.Code != nil && alg.Code.Inline != nil {
+		appendVolumes(groupSpec, map[string]interface{}{
+			"name": InlineCodeVolumeName,
+			"configMap": map[string]interface{}{
+				"name": CodeConfigMapName(req.JobName),
+				"items": []interface{}{
+					map[string]interface{}{"key": inlineCodeScriptKey, "path": inlineFileName(alg.Code.Inline)},
+				},
+			},
+		})
+	}
+
+	var fetch map[string]interface{}
+	if alg.Code != nil {
+		switch {
+		case alg.Code.Git != nil:
+			fetch = gitFetchContainer(alg.Code.Git)
+		case alg.Code.S3 != nil:
+			fetch = s3FetchContainer(alg.Code.S3, StorageSecretName(req.JobName))
+		}
+	}
+	if fetch != nil {
+		appendInitContainers(groupSpec, fetch)
+	}
+	appendInitContainers(groupSpec, prepareWorkspaceContainer(alg, hyperparametersJSON))
+}
+
+// gitFetchContainer clones a single ref of a repository into the code directory
+func gitFetchContainer(src *models.GitCodeSource) map[string]interface{} {
+	ref := src.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	script := strings.Join([]string{
+		"set -e",
+		"git init -q " + CodeDir,
+		"cd " + CodeDir,
+		`git remote add origin "$GIT_REPOSITORY"`,
+		`git fetch -q --depth 1 origin "$GIT_REF"`,
+		"git checkout -q FETCH_HEAD",
+	}, "\n")
+
+	return map[string]interface{}{
+		"name":    "fetch-code",
+		"image":   DefaultGitImage,
+		"command": []string{"sh", "-c", script},
+		"env": []interface{}{
+			envVar("GIT_REPOSITORY", src.Repository),
+			envVar("GIT_REF", ref),
+		},
+		"volumeMounts": []interface{}{volumeMount(WorkspaceVolumeName, WorkspaceMountPath)},
+	}
+}
+
+// s3FetchContainer downloads the code archive from object storage, with the
+// MinIO alias read from the job's storage Secret
+func s3FetchContainer(src *models.S3CodeSource, secretName string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":  "fetch-code",
+		"image": DefaultMinioClientImage,
+		"args": []string{
+			"cp", "--quiet",
+			fmt.Sprintf("code/%s/%s", src.Bucket, strings.TrimPrefix(src.Key, "/")),
+			codeArchivePath,
+		},
+		"env": []interface{}{
+			secretEnvVar("MC_HOST_code", secretName, storageSecretCodeHost),
+		},
+		"volumeMounts": []interface{}{volumeMount(WorkspaceVolumeName, WorkspaceMountPath)},
+	}
+}
+
+// prepareWorkspaceContainer unpacks downloaded archives, copies inline scripts
+// and writes the hyperparameters file, and makes the workspace writable for
+// the ray user
+func prepareWorkspaceContainer(alg *models.Algorithm, hyperparametersJSON string) map[string]interface{} {
+	lines := []string{"set -e", "mkdir -p " + CodeDir}
+	mounts := []interface{}{volumeMount(WorkspaceVolumeName, WorkspaceMountPath)}
+
+	if alg.Code != nil && alg.Code.S3 != nil {
+		lines = append(lines, extractArchiveCommand(alg.Code.S3.Key), "rm -f "+codeArchivePath)
+	}
+	if alg.Code != nil && alg.Code.Inline != nil {
+		fileName := inlineFileName(alg.Code.Inline)
+		lines = append(lines, fmt.Sprintf("cp %s/%s %s/%s", inlineCodeMountPath, fileName, CodeDir, fileName))
+		mounts = append(mounts, volumeMount(InlineCodeVolumeName, inlineCodeMountPath))
+	}
+	lines = append(lines,
+		fmt.Sprintf(`printf '%%s' "$HYPERPARAMETERS_JSON" > %s`, HyperparametersFile),
+		"chmod -R a+rwX "+WorkspaceMountPath,
+	)
+
+	return map[string]interface{}{
+		"name":         "prepare-workspace",
+		"image":        DefaultUtilityImage,
+		"command":      []string{"sh", "-c", strings.Join(lines, "\n")},
+		"env":          []interface{}{envVar("HYPERPARAMETERS_JSON", hyperparametersJSON)},
+		"volumeMounts": mounts,
+	}
+}
+
+// extractArchiveCommand picks the unpack command from the archive extension
+func extractArchiveCommand(key string) string {
+	switch {
+	case strings.HasSuffix(key, ".zip"):
+		return fmt.Sprintf("unzip -q -o %s -d %s", codeArchivePath, CodeDir)
+	case strings.HasSuffix(key, ".tar.gz"), strings.HasSuffix(key, ".tgz"):
+		return fmt.Sprintf("tar -xzf %s -C %s", codeArchivePath, CodeDir)
+	default:
+		return fmt.Sprintf("tar -xf %s -C %s", codeArchivePath, CodeDir)
+	}
+}
+
+// minioHostURL builds the MC_HOST_<alias> value for an S3 endpoint using the platform credentials
+func minioHostURL(endpoint string, credentials config.StorageCredentials) (string, error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+	u.User = url.UserPassword(credentials.AccessKey, credentials.SecretKey)
+	u.Path = ""
+	return u.String(), nil
+}
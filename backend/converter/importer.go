@@ -0,0 +1,538 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+
+	"github.com/loiht2/ml-platform-training-job/backend/config"
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// xgboostEnvNames maps XGBoost hyperparameter JSON names to the env var names
+// appendXGBoostHyperparameters emits when they are not just the upper-cased JSON name
+var xgboostEnvNames = map[string]string{
+	"num_round":   "NUM_BOOST_ROUND",
+	"csv_weights": "CSV_WEIGHT",
+}
+
+// xgboostOptionalEnvVars are the XGBoost env vars appendXGBoostHyperparameters
+// leaves out at their defaults or never renders; it renders every other one
+var xgboostOptionalEnvVars = map[string]bool{
+	"NTHREAD":     true,
+	"UPDATER":     true,
+	"EVAL_METRIC": true,
+}
+
+// Env vars rendered by buildRuntimeEnvYAML from other request fields; they are
+// consumed while importing and never become custom hyperparameters
+var derivedEnvVars = map[string]bool{
+	"NUM_WORKER": true, "USE_GPU": true, "RUN_NAME": true, "STORAGE_PATH": true,
+	"S3_ENDPOINT": true, "S3_ACCESS_KEY": true, "S3_SECRET_KEY": true, "S3_REGION": true,
+	"S3_BUCKET": true, "S3_TRAIN_KEY": true, "S3_VAL_KEY": true,
+	"ARTIFACT_URI": true, "AWS_ENDPOINT_URL": true, "AWS_ACCESS_KEY_ID": true,
+	"AWS_SECRET_ACCESS_KEY": true, "AWS_DEFAULT_REGION": true,
+	"LOCAL_DATA_DIR": true, "LOCAL_TRAIN_PATH": true, "LOCAL_VAL_PATH": true,
+}
+
+// rayJobImport collects the result of reverse-mapping a RayJob manifest
+type rayJobImport struct {
+	req      *models.TrainingJobRequest
+	unmapped []string
+	defaults config.JobDefaults
+}
+
+func (imp *rayJobImport) skip(field string, format string, args ...interface{}) {
+	imp.unmapped = append(imp.unmapped, fmt.Sprintf("%s: %s", field, fmt.Sprintf(format, args...)))
+}
+
+// ImportRayJob reverse-maps a RayJob manifest (YAML or JSON) into a training job
+// request. Fields without an equivalent in the request are returned as
+// human-readable entries of the unmapped list.
+func (c *Converter) ImportRayJob(manifest []byte) (*models.TrainingJobRequest, []string, error) {
+	var obj map[string]interface{}
+	if err := yaml.Unmarshal(manifest, &obj); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	apiVersion, _ := obj["apiVersion"].(string)
+	kind, _ := obj["kind"].(string)
+	if kind != "RayJob" || !strings.HasPrefix(apiVersion, "ray.io/") {
+		return nil, nil, fmt.Errorf("expected a ray.io RayJob manifest, got %s %s", apiVersion, kind)
+	}
+
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	spec, _ := obj["spec"].(map[string]interface{})
+	if spec == nil {
+		return nil, nil, fmt.Errorf("manifest has no spec")
+	}
+
+	imp := &rayJobImport{
+		req: &models.TrainingJobRequest{
+			Algorithm: models.Algorithm{Source: AlgorithmSourceBuiltin},
+		},
+		defaults: c.defaults,
+	}
+	imp.importMetadata(metadata)
+	imp.importSpec(spec)
+
+	if imp.req.JobName == "" {
+		return nil, nil, fmt.Errorf("manifest has no metadata.name")
+	}
+	if imp.req.Algorithm.AlgorithmName == "" {
+		imp.req.Algorithm.AlgorithmName = "ray"
+		if imp.req.Hyperparameters.XGBoost != nil {
+			imp.req.Algorithm.AlgorithmName = "xgboost"
+		}
+	}
+
+	// Default images are filled in again on conversion
+	defaultImage := c.defaults.ImageFor(imp.req.Algorithm.AlgorithmName)
+	if imp.req.HeadImage == defaultImage {
+		imp.req.HeadImage = ""
+	}
+	if imp.req.WorkerImage == defaultImage {
+		imp.req.WorkerImage = ""
+	}
+
+	sort.Strings(imp.unmapped)
+	return imp.req, imp.unmapped, nil
+}
+
+func (imp *rayJobImport) importMetadata(metadata map[string]interface{}) {
+	imp.req.JobName, _ = metadata["name"].(string)
+	imp.req.Namespace, _ = metadata["namespace"].(string)
+
+	labels, _ := metadata["labels"].(map[string]interface{})
+	for key, value := range labels {
+		switch key {
+		case "algorithm":
+			imp.req.Algorithm.AlgorithmName, _ = value.(string)
+		case "app", "training-job-id":
+			// Set again on submission
+		default:
+			imp.skip("metadata.labels."+key, "labels are not carried over")
+		}
+	}
+
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+	for key := range annotations {
+		if key != "training-job-id" && key != "kubectl.kubernetes.io/last-applied-configuration" {
+			imp.skip("metadata.annotations."+key, "annotations are not carried over")
+		}
+	}
+}
+
+func (imp *rayJobImport) importSpec(spec map[string]interface{}) {
+	for key, value := range spec {
+		switch key {
+		case "entrypoint":
+			entrypoint, _ := value.(string)
+			if entrypoint != DefaultEntrypoint {
+				imp.req.Entrypoint = entrypoint
+			}
+		case "runtimeEnvYAML":
+			runtimeEnv, _ := value.(string)
+			imp.importRuntimeEnv(runtimeEnv)
+		case "rayClusterSpec":
+			clusterSpec, _ := value.(map[string]interface{})
+			imp.importClusterSpec(clusterSpec)
+		case "activeDeadlineSeconds":
+			seconds, _ := value.(float64)
+			imp.req.StoppingCondition.MaxRuntimeSeconds = int(seconds)
+		case "shutdownAfterJobFinishes", "ttlSecondsAfterFinished":
+			// Lifecycle settings managed by the platform
+		case "suspend":
+			// Set by the queueing integration on submission
+		default:
+			imp.skip("spec."+key, "not supported by the platform")
+		}
+	}
+}
+
+func (imp *rayJobImport) importClusterSpec(clusterSpec map[string]interface{}) {
+	if clusterSpec == nil {
+		imp.skip("spec.rayClusterSpec", "missing; clusterSelector-based RayJobs cannot be imported")
+		return
+	}
+
+	if version, _ := clusterSpec["rayVersion"].(string); version != "" && version != imp.defaults.RayVersion {
+		imp.skip("spec.rayClusterSpec.rayVersion", "%s is replaced by the platform's %s", version, imp.defaults.RayVersion)
+	}
+	for key := range clusterSpec {
+		switch key {
+		case "rayVersion", "headGroupSpec", "workerGroupSpecs":
+		default:
+			imp.skip("spec.rayClusterSpec."+key, "not supported by the platform")
+		}
+	}
+
+	head, _ := clusterSpec["headGroupSpec"].(map[string]interface{})
+	headResources := imp.importGroup("spec.rayClusterSpec.headGroupSpec", head, &imp.req.HeadImage)
+
+	workers, _ := clusterSpec["workerGroupSpecs"].([]interface{})
+	for i := 1; i < len(workers); i++ {
+		imp.skip(fmt.Sprintf("spec.rayClusterSpec.workerGroupSpecs[%d]", i), "only one worker group is supported")
+	}
+	if len(workers) == 0 {
+		// Without workers, size the instances after the head
+		imp.req.Resources.InstanceResources = headResources
+		return
+	}
+
+	worker, _ := workers[0].(map[string]interface{})
+	workerResources := imp.importGroup("spec.rayClusterSpec.workerGroupSpecs[0]", worker, &imp.req.WorkerImage)
+	imp.req.Resources.InstanceResources = workerResources
+	if replicas, ok := worker["replicas"].(float64); ok {
+		imp.req.Resources.InstanceCount = int(replicas)
+	}
+	if headResources.CPUCores != workerResources.CPUCores || headResources.MemoryGiB != workerResources.MemoryGiB {
+		imp.skip("spec.rayClusterSpec.headGroupSpec.template.spec.containers[0].resources",
+			"head resources differ from the workers; the worker resources are used for both")
+	}
+}
+
+// importGroup maps one Ray group spec and returns its container resources
+func (imp *rayJobImport) importGroup(field string, group map[string]interface{}, image *string) models.InstanceResources {
+	var res models.InstanceResources
+	if group == nil {
+		return res
+	}
+
+	for key := range group {
+		switch key {
+		case "template", "replicas", "minReplicas", "maxReplicas", "groupName":
+		case "rayStartParams":
+			if params, _ := group[key].(map[string]interface{}); len(params) > 0 {
+				imp.skip(field+".rayStartParams", "custom Ray start parameters are not supported")
+			}
+		default:
+			imp.skip(field+"."+key, "not supported by the platform")
+		}
+	}
+
+	template, _ := group["template"].(map[string]interface{})
+	podSpec, _ := template["spec"].(map[string]interface{})
+	containers, _ := podSpec["containers"].([]interface{})
+	if len(containers) == 0 {
+		imp.skip(field+".template.spec.containers", "missing")
+		return res
+	}
+	for i := 1; i < len(containers); i++ {
+		imp.skip(fmt.Sprintf("%s.template.spec.containers[%d]", field, i), "sidecar containers are not supported")
+	}
+
+	container, _ := containers[0].(map[string]interface{})
+	if img, _ := container["image"].(string); img != "" {
+		*image = img
+	}
+	for key := range container {
+		switch key {
+		case "name", "image", "ports", "resources", "volumeMounts", "lifecycle":
+		case "env":
+			imp.importContainerEnv(fmt.Sprintf("%s.template.spec.containers[0].env", field), container["env"])
+		default:
+			imp.skip(fmt.Sprintf("%s.template.spec.containers[0].%s", field, key), "not supported by the platform")
+		}
+	}
+
+	resources, _ := container["resources"].(map[string]interface{})
+	requests, _ := resources["requests"].(map[string]interface{})
+	if len(requests) == 0 {
+		requests, _ = resources["limits"].(map[string]interface{})
+	}
+	res.CPUCores = int(imp.quantity(field+" cpu", requests["cpu"], 1))
+	res.MemoryGiB = int(imp.quantity(field+" memory", requests["memory"], 1<<30))
+	res.GPUCount = int(imp.quantity(field+" nvidia.com/gpu", requests["nvidia.com/gpu"], 1))
+
+	imp.importPodSpec(field+".template.spec", podSpec)
+	return res
+}
+
+// importContainerEnv accepts the env applyStorageEnv reads from the job's
+// storage Secret, which is created again on submission, and reports the rest
+func (imp *rayJobImport) importContainerEnv(field string, value interface{}) {
+	env, _ := value.([]interface{})
+	for i, v := range env {
+		entry, _ := v.(map[string]interface{})
+		valueFrom, _ := entry["valueFrom"].(map[string]interface{})
+		secretKeyRef, _ := valueFrom["secretKeyRef"].(map[string]interface{})
+		if secretName, _ := secretKeyRef["name"].(string); secretName != "" && secretName == StorageSecretName(imp.req.JobName) {
+			continue
+		}
+		name, _ := entry["name"].(string)
+		imp.skip(fmt.Sprintf("%s[%d]", field, i), "container env %q is not supported", name)
+	}
+}
+
+// quantity parses a resource quantity and rounds it up to whole units
+func (imp *rayJobImport) quantity(field string, value interface{}, unit float64) int64 {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case string:
+		text = v
+	case float64:
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		imp.skip(field, "unexpected quantity %v", value)
+		return 0
+	}
+
+	q, err := resource.ParseQuantity(text)
+	if err != nil {
+		imp.skip(field, "invalid quantity %q", text)
+		return 0
+	}
+	return int64(math.Ceil(q.AsApproximateFloat64() / unit))
+}
+
+func (imp *rayJobImport) importPodSpec(field string, podSpec map[string]interface{}) {
+	for key, value := range podSpec {
+		switch key {
+		case "containers", "volumes":
+		case "priorityClassName", "schedulerName":
+			// Derived from the job priority and queueing settings on submission
+		case "initContainers":
+			imp.skip(field+".initContainers", "init containers are not supported")
+		default:
+			if value != nil {
+				imp.skip(field+"."+key, "not supported by the platform")
+			}
+		}
+	}
+
+	volumes, _ := podSpec["volumes"].([]interface{})
+	for i, v := range volumes {
+		volume, _ := v.(map[string]interface{})
+		name, _ := volume["name"].(string)
+
+		if pvc, ok := volume["persistentVolumeClaim"].(map[string]interface{}); ok && name == "result-storage" {
+			if claimName, _ := pvc["claimName"].(string); claimName != "" && claimName != imp.defaults.PVCName {
+				imp.req.PVCName = claimName
+			}
+			continue
+		}
+		if emptyDir, ok := volume["emptyDir"].(map[string]interface{}); ok {
+			switch name {
+			case SharedMemoryVolumeName:
+				imp.req.Resources.SharedMemoryGiB = int(imp.quantity(field+".volumes.dshm", emptyDir["sizeLimit"], 1<<30))
+				continue
+			case ScratchVolumeName:
+				imp.req.Resources.ScratchVolumeGiB = int(imp.quantity(field+".volumes.scratch", emptyDir["sizeLimit"], 1<<30))
+				continue
+			}
+		}
+		imp.skip(fmt.Sprintf("%s.volumes[%d]", field, i), "volume %q is not supported", name)
+	}
+}
+
+// importRuntimeEnv maps the env vars written by buildRuntimeEnvYAML back to request fields
+func (imp *rayJobImport) importRuntimeEnv(runtimeEnvYAML string) {
+	var runtimeEnv map[string]interface{}
+	if err := yaml.Unmarshal([]byte(runtimeEnvYAML), &runtimeEnv); err != nil {
+		imp.skip("spec.runtimeEnvYAML", "cannot be parsed: %v", err)
+		return
+	}
+
+	env := map[string]string{}
+	for key, value := range runtimeEnv {
+		if key != "env_vars" {
+			imp.skip("spec.runtimeEnvYAML."+key, "only env_vars are supported")
+			continue
+		}
+		vars, _ := value.(map[string]interface{})
+		for name, v := range vars {
+			env[name] = fmt.Sprint(v)
+		}
+	}
+
+	imp.importStorageEnv(env)
+	imp.importXGBoostEnv(env)
+
+	if _, ok := env["HYPERPARAMETERS_FILE"]; ok {
+		imp.skip("spec.runtimeEnvYAML.env_vars.HYPERPARAMETERS_FILE", "custom-code jobs cannot be imported; resubmit them with source \"custom\"")
+		delete(env, "HYPERPARAMETERS_FILE")
+		delete(env, "CODE_DIR")
+		delete(env, "PYTHONPATH")
+	}
+	if label, ok := env["LABEL_COLUMN"]; ok {
+		if label != DefaultLabelColumn {
+			imp.skip("spec.runtimeEnvYAML.env_vars.LABEL_COLUMN", "%q differs from the platform's %q", label, DefaultLabelColumn)
+		}
+		delete(env, "LABEL_COLUMN")
+	}
+	if runName, ok := env["RUN_NAME"]; ok && runName != imp.req.JobName && imp.req.JobName != "" {
+		imp.skip("spec.runtimeEnvYAML.env_vars.RUN_NAME", "%q is replaced by the job name", runName)
+	}
+
+	// Whatever is left round-trips through customHyperparameters, which are rendered upper-cased
+	for name, value := range env {
+		if derivedEnvVars[name] {
+			continue
+		}
+		if imp.req.CustomHyperparameters == nil {
+			imp.req.CustomHyperparameters = map[string]interface{}{}
+		}
+		imp.req.CustomHyperparameters[strings.ToLower(name)] = value
+	}
+}
+
+// importStorageEnv maps the S3 input, artifact and staging env vars
+func (imp *rayJobImport) importStorageEnv(env map[string]string) {
+	if bucket := env["S3_BUCKET"]; bucket != "" || env["S3_TRAIN_KEY"] != "" {
+		imp.req.InputDataConfig = append(imp.req.InputDataConfig, models.InputDataConfig{
+			ChannelName:     "train",
+			SourceType:      "object-storage",
+			StorageProvider: "minio",
+			Endpoint:        env["S3_ENDPOINT"],
+			Bucket:          bucket,
+			Prefix:          env["S3_TRAIN_KEY"],
+		})
+		if valKey := env["S3_VAL_KEY"]; valKey != "" {
+			imp.req.InputDataConfig = append(imp.req.InputDataConfig, models.InputDataConfig{
+				ChannelName:     "validation",
+				SourceType:      "object-storage",
+				StorageProvider: "minio",
+				Endpoint:        env["S3_ENDPOINT"],
+				Bucket:          bucket,
+				Prefix:          valKey,
+			})
+		}
+	}
+	if key, ok := env["S3_ACCESS_KEY"]; ok && key != imp.defaults.Storage.AccessKey {
+		imp.skip("spec.runtimeEnvYAML.env_vars.S3_ACCESS_KEY", "credentials are not imported; the platform credentials are used")
+	}
+
+	switch storagePath := env["STORAGE_PATH"]; {
+	case storagePath == "" || storagePath == DefaultStoragePath:
+	case isS3ArtifactURI(storagePath):
+		imp.req.OutputDataConfig.ArtifactURI = storagePath
+		imp.req.OutputDataConfig.Endpoint = env["AWS_ENDPOINT_URL"]
+	case strings.HasPrefix(storagePath, "/"):
+		imp.req.OutputDataConfig.ArtifactURI = "file://" + storagePath
+	default:
+		imp.skip("spec.runtimeEnvYAML.env_vars.STORAGE_PATH", "unsupported storage path %q", storagePath)
+	}
+
+	if _, ok := env["LOCAL_DATA_DIR"]; ok {
+		imp.req.StageInputData = true
+	}
+}
+
+// importXGBoostEnv maps the env vars appendXGBoostHyperparameters emits back
+// to XGBoost hyperparameters. A partial set is reported and left to the
+// custom hyperparameters.
+func (imp *rayJobImport) importXGBoostEnv(env map[string]string) {
+	t := reflect.TypeOf(models.XGBoostHyperparameters{})
+	envNames := make([]string, t.NumField())
+	var present, missing []string
+	for i := 0; i < t.NumField(); i++ {
+		jsonName := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		envName, ok := xgboostEnvNames[jsonName]
+		if !ok {
+			envName = strings.ToUpper(jsonName)
+		}
+		envNames[i] = envName
+
+		if _, ok := env[envName]; ok {
+			present = append(present, envName)
+		} else if !xgboostOptionalEnvVars[envName] {
+			missing = append(missing, envName)
+		}
+	}
+	if len(present) == 0 {
+		return
+	}
+
+	// Typed hyperparameters always render every field, so a partial set would
+	// come back with zeros in place of the script's defaults
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		imp.skip("spec.runtimeEnvYAML.env_vars", "XGBoost hyperparameters %s are missing; the others are kept as custom hyperparameters",
+			strings.Join(missing, ", "))
+		return
+	}
+
+	params := map[string]interface{}{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+		envName := envNames[i]
+
+		raw, ok := env[envName]
+		if !ok {
+			continue
+		}
+		delete(env, envName)
+
+		value, err := parseEnvValue(raw, field.Type)
+		if err != nil {
+			imp.skip("spec.runtimeEnvYAML.env_vars."+envName, "%v", err)
+			continue
+		}
+		if value != nil {
+			params[jsonName] = value
+		}
+	}
+
+	// The emitter leaves out the default updater
+	if _, ok := params["updater"]; !ok {
+		params["updater"] = "auto"
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		imp.skip("spec.runtimeEnvYAML.env_vars", "failed to map XGBoost hyperparameters: %v", err)
+		return
+	}
+	var xgb models.XGBoostHyperparameters
+	if err := json.Unmarshal(data, &xgb); err != nil {
+		imp.skip("spec.runtimeEnvYAML.env_vars", "failed to map XGBoost hyperparameters: %v", err)
+		return
+	}
+	imp.req.Hyperparameters.XGBoost = &xgb
+}
+
+// parseEnvValue converts an env var string to a value of the given field type.
+// An empty value of an optional field yields nil.
+func parseEnvValue(raw string, t reflect.Type) (interface{}, error) {
+	if t.Kind() == reflect.Ptr {
+		if raw == "" {
+			return nil, nil
+		}
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return raw, nil
+	case reflect.Int:
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", raw)
+		}
+		return v, nil
+	case reflect.Float64:
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", raw)
+		}
+		return v, nil
+	case reflect.Slice:
+		if raw == "" {
+			return []string{}, nil
+		}
+		return strings.Split(raw, ","), nil
+	default:
+		return nil, fmt.Errorf("unsupported field type %s", t)
+	}
+}
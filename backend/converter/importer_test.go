@@ -0,0 +1,221 @@
+package converter
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/loiht2/ml-platform-training-job/backend/config"
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// rayJobManifest renders a RayJob with the given runtime env and extra fields
+func rayJobManifest(t *testing.T, runtimeEnvYAML string, change func(rayJob map[string]interface{})) []byte {
+	t.Helper()
+	rayJob := map[string]interface{}{
+		"apiVersion": "ray.io/v1",
+		"kind":       "RayJob",
+		"metadata":   map[string]interface{}{"name": "churn", "namespace": "ml"},
+		"spec": map[string]interface{}{
+			"entrypoint":     DefaultEntrypoint,
+			"runtimeEnvYAML": runtimeEnvYAML,
+			"rayClusterSpec": map[string]interface{}{
+				"headGroupSpec": map[string]interface{}{
+					"template": map[string]interface{}{"spec": map[string]interface{}{
+						"containers": []interface{}{map[string]interface{}{"name": "ray-head", "image": "ray:custom"}},
+					}},
+				},
+			},
+		},
+	}
+	if change != nil {
+		change(rayJob)
+	}
+	data, err := json.Marshal(rayJob)
+	if err != nil {
+		t.Fatalf("failed to marshal manifest: %v", err)
+	}
+	return data
+}
+
+func TestImportRayJobRoundTripsConvertedJob(t *testing.T) {
+	c := NewConverter(&config.Settings{})
+	earlyStopping := 10
+	req := &models.TrainingJobRequest{
+		JobName:   "churn",
+		Namespace: "ml",
+		Algorithm: models.Algorithm{Source: AlgorithmSourceBuiltin, AlgorithmName: "xgboost"},
+		Resources: models.Resources{
+			InstanceResources: models.InstanceResources{CPUCores: 4, MemoryGiB: 16},
+			InstanceCount:     2,
+		},
+		Hyperparameters: models.HyperparametersMap{XGBoost: &models.XGBoostHyperparameters{
+			EarlyStoppingRounds: &earlyStopping,
+			NumRound:            200,
+			Booster:             "gbtree",
+			Eta:                 0.3,
+			MaxDepth:            6,
+			Subsample:           0.8,
+			TreeMethod:          "hist",
+			Updater:             "auto",
+			Objective:           "binary:logistic",
+			EvalMetric:          []string{"auc", "logloss"},
+		}},
+	}
+
+	rayJob, err := c.ConvertToRayJobV2(req, "churn-1")
+	if err != nil {
+		t.Fatalf("ConvertToRayJobV2 failed: %v", err)
+	}
+	manifest, err := json.Marshal(rayJob)
+	if err != nil {
+		t.Fatalf("failed to marshal RayJob: %v", err)
+	}
+
+	imported, unmapped, err := c.ImportRayJob(manifest)
+	if err != nil {
+		t.Fatalf("ImportRayJob failed: %v", err)
+	}
+	if len(unmapped) != 0 {
+		t.Errorf("unmapped = %v, want none", unmapped)
+	}
+	if !reflect.DeepEqual(imported.Hyperparameters.XGBoost, req.Hyperparameters.XGBoost) {
+		t.Errorf("XGBoost hyperparameters = %+v, want %+v", imported.Hyperparameters.XGBoost, req.Hyperparameters.XGBoost)
+	}
+	if len(imported.CustomHyperparameters) != 0 {
+		t.Errorf("custom hyperparameters = %v, want none", imported.CustomHyperparameters)
+	}
+	if imported.Resources.InstanceCount != 2 || imported.Resources.InstanceResources != req.Resources.InstanceResources {
+		t.Errorf("resources = %+v, want %+v", imported.Resources, req.Resources)
+	}
+}
+
+func TestImportRayJob(t *testing.T) {
+	tests := []struct {
+		name       string
+		runtimeEnv string
+		change     func(rayJob map[string]interface{})
+		check      func(t *testing.T, req *models.TrainingJobRequest)
+		unmapped   []string
+	}{
+		{
+			name:       "partial XGBoost env stays custom",
+			runtimeEnv: "env_vars:\n  NUM_BOOST_ROUND: \"50\"\n  ETA: \"0.1\"\n",
+			check: func(t *testing.T, req *models.TrainingJobRequest) {
+				if req.Hyperparameters.XGBoost != nil {
+					t.Errorf("XGBoost hyperparameters = %+v, want none for a partial set", req.Hyperparameters.XGBoost)
+				}
+				want := map[string]interface{}{"num_boost_round": "50", "eta": "0.1"}
+				if !reflect.DeepEqual(req.CustomHyperparameters, want) {
+					t.Errorf("custom hyperparameters = %v, want %v", req.CustomHyperparameters, want)
+				}
+				if req.Algorithm.AlgorithmName != "ray" {
+					t.Errorf("algorithm = %q, want ray", req.Algorithm.AlgorithmName)
+				}
+			},
+			unmapped: []string{"spec.runtimeEnvYAML.env_vars: XGBoost hyperparameters"},
+		},
+		{
+			name:       "storage env",
+			runtimeEnv: "env_vars:\n  S3_ENDPOINT: \"minio:9000\"\n  S3_BUCKET: \"data\"\n  S3_TRAIN_KEY: \"train/\"\n  S3_VAL_KEY: \"val/\"\n",
+			check: func(t *testing.T, req *models.TrainingJobRequest) {
+				if len(req.InputDataConfig) != 2 || req.InputDataConfig[0].Prefix != "train/" || req.InputDataConfig[1].Prefix != "val/" {
+					t.Errorf("input channels = %+v, want train and validation", req.InputDataConfig)
+				}
+				if len(req.CustomHyperparameters) != 0 {
+					t.Errorf("custom hyperparameters = %v, want none", req.CustomHyperparameters)
+				}
+			},
+		},
+		{
+			name:       "unsupported fields",
+			runtimeEnv: "env_vars: {}\npip: [torch]\n",
+			change: func(rayJob map[string]interface{}) {
+				metadata := rayJob["metadata"].(map[string]interface{})
+				metadata["labels"] = map[string]interface{}{"team": "churn"}
+				spec := rayJob["spec"].(map[string]interface{})
+				spec["submitterPodTemplate"] = map[string]interface{}{}
+			},
+			check: func(t *testing.T, req *models.TrainingJobRequest) {
+				if req.HeadImage != "ray:custom" {
+					t.Errorf("head image = %q, want ray:custom", req.HeadImage)
+				}
+			},
+			unmapped: []string{
+				"metadata.labels.team: labels are not carried over",
+				"spec.runtimeEnvYAML.pip: only env_vars are supported",
+				"spec.submitterPodTemplate: not supported by the platform",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConverter(&config.Settings{})
+			req, unmapped, err := c.ImportRayJob(rayJobManifest(t, tt.runtimeEnv, tt.change))
+			if err != nil {
+				t.Fatalf("ImportRayJob failed: %v", err)
+			}
+			tt.check(t, req)
+
+			if len(unmapped) != len(tt.unmapped) {
+				t.Fatalf("unmapped = %q, want entries starting with %q", unmapped, tt.unmapped)
+			}
+			for i, prefix := range tt.unmapped {
+				if !strings.HasPrefix(unmapped[i], prefix) {
+					t.Errorf("unmapped[%d] = %q, want it to start with %q", i, unmapped[i], prefix)
+				}
+			}
+		})
+	}
+}
+
+func TestImportRayJobRejectsOtherKinds(t *testing.T) {
+	c := NewConverter(&config.Settings{})
+	if _, _, err := c.ImportRayJob([]byte("apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: churn\n")); err == nil {
+		t.Error("ImportRayJob accepted a batch Job")
+	}
+}
+
+func TestImportRayJobAcceptsStorageSecretEnv(t *testing.T) {
+	c := NewConverter(&config.Settings{Defaults: &config.JobDefaults{
+		Storage: config.StorageCredentials{AccessKey: "platform-key", SecretKey: "platform-secret"},
+	}})
+	req := &models.TrainingJobRequest{
+		JobName:          "churn",
+		Algorithm:        models.Algorithm{AlgorithmName: "ray"},
+		Resources:        models.Resources{InstanceCount: 1},
+		InputDataConfig:  []models.InputDataConfig{{Endpoint: "minio.local:9000", Bucket: "data", Prefix: "train/"}},
+		OutputDataConfig: models.OutputDataConfig{ArtifactURI: "s3://models/churn"},
+	}
+	rayJob, err := c.ConvertToRayJobV2(req, "churn-1")
+	if err != nil {
+		t.Fatalf("ConvertToRayJobV2 failed: %v", err)
+	}
+	manifest, _ := json.Marshal(rayJob)
+
+	_, unmapped, err := c.ImportRayJob(manifest)
+	if err != nil {
+		t.Fatalf("ImportRayJob failed: %v", err)
+	}
+	if len(unmapped) != 0 {
+		t.Errorf("unmapped = %v, want none", unmapped)
+	}
+
+	// Env read from any other Secret is not the platform's
+	manifest = rayJobManifest(t, "env_vars: {}\n", func(rayJob map[string]interface{}) {
+		spec := rayJob["spec"].(map[string]interface{})
+		head := spec["rayClusterSpec"].(map[string]interface{})["headGroupSpec"].(map[string]interface{})
+		container := head["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})[0].(map[string]interface{})
+		container["env"] = []interface{}{secretEnvVar("TOKEN", "other", "token")}
+	})
+	_, unmapped, err = c.ImportRayJob(manifest)
+	if err != nil {
+		t.Fatalf("ImportRayJob failed: %v", err)
+	}
+	want := []string{`spec.rayClusterSpec.headGroupSpec.template.spec.containers[0].env[0]: container env "TOKEN" is not supported`}
+	if !reflect.DeepEqual(unmapped, want) {
+		t.Errorf("unmapped = %q, want %q", unmapped, want)
+	}
+}
@@ -0,0 +1,101 @@
+package converter
+
+// Helpers for editing the head and worker group specs built by this package.
+// The group specs are plain maps, so these only work on maps produced by
+// buildRayHeadGroupSpecV2 and buildRayWorkerGroupSpecV2.
+
+// podSpec returns the pod spec map of a Ray group spec
+func podSpec(groupSpec map[string]interface{}) map[string]interface{} {
+	template := groupSpec["template"].(map[string]interface{})
+	return template["spec"].(map[string]interface{})
+}
+
+// appendInitContainers adds init containers to the group's pod template
+func appendInitContainers(groupSpec map[string]interface{}, containers ...map[string]interface{}) {
+	spec := podSpec(groupSpec)
+	existing, _ := spec["initContainers"].([]interface{})
+	for _, container := range containers {
+		existing = append(existing, container)
+	}
+	spec["initContainers"] = existing
+}
+
+// appendVolumes adds volumes to the group's pod template
+func appendVolumes(groupSpec map[string]interface{}, volumes ...map[string]interface{}) {
+	spec := podSpec(groupSpec)
+	existing, _ := spec["volumes"].([]interface{})
+	for _, volume := range volumes {
+		existing = append(existing, volume)
+	}
+	spec["volumes"] = existing
+}
+
+// appendVolumeMounts adds volume mounts to every main container of the group
+func appendVolumeMounts(groupSpec map[string]interface{}, mounts ...map[string]interface{}) {
+	containers, _ := podSpec(groupSpec)["containers"].([]interface{})
+	for _, c := range containers {
+		container := c.(map[string]interface{})
+		existing, _ := container["volumeMounts"].([]interface{})
+		for _, mount := range mounts {
+			existing = append(existing, mount)
+		}
+		container["volumeMounts"] = existing
+	}
+}
+
+// emptyDirVolume builds an emptyDir volume, optionally in memory and with a size limit
+func emptyDirVolume(name, medium, sizeLimit string) map[string]interface{} {
+	emptyDir := map[string]interface{}{}
+	if medium != "" {
+		emptyDir["medium"] = medium
+	}
+	if sizeLimit != "" {
+		emptyDir["sizeLimit"] = sizeLimit
+	}
+	return map[string]interface{}{
+		"name":     name,
+		"emptyDir": emptyDir,
+	}
+}
+
+// volumeMount builds a volume mount entry
+func volumeMount(name, mountPath string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":      name,
+		"mountPath": mountPath,
+	}
+}
+
+// appendEnv adds env vars to every main container of the group
+func appendEnv(groupSpec map[string]interface{}, vars ...map[string]interface{}) {
+	containers, _ := podSpec(groupSpec)["containers"].([]interface{})
+	for _, c := range containers {
+		container := c.(map[string]interface{})
+		existing, _ := container["env"].([]interface{})
+		for _, v := range vars {
+			existing = append(existing, v)
+		}
+		container["env"] = existing
+	}
+}
+
+// secretEnvVar builds a container env entry read from a Secret key
+func secretEnvVar(name, secretName, key string) map[string]interface{} {
+	return map[string]interface{}{
+		"name": name,
+		"valueFrom": map[string]interface{}{
+			"secretKeyRef": map[string]interface{}{
+				"name": secretName,
+				"key":  key,
+			},
+		},
+	}
+}
+
+// envVar builds a container env entry
+func envVar(name, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":  name,
+		"value": value,
+	}
+}
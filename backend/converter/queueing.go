@@ -0,0 +1,54 @@
+package converter
+
+import (
+	"fmt"
+
+	"github.com/loiht2/ml-platform-training-job/backend/config"
+)
+
+// Labels read by Kueue and by KubeRay's batch scheduler integration
+const (
+	KueueQueueNameLabel     = "kueue.x-k8s.io/queue-name"
+	RaySchedulerNameLabel   = "ray.io/scheduler-name"
+	VolcanoQueueNameLabel   = "volcano.sh/queue-name"
+	DefaultVolcanoScheduler = "volcano"
+)
+
+// applyQueueing hands the RayJob over to the cluster's queueing integration.
+//
+// Kueue: the RayJob is created suspended with the LocalQueue label; Kueue
+// unsuspends it once the whole cluster fits in the queue's quota.
+//
+// Volcano: the RayJob is labelled for KubeRay's Volcano batch scheduler, which
+// creates a PodGroup covering the head and all workers, and the pods are bound
+// by the Volcano scheduler so they start all together or not at all.
+func (c *Converter) applyQueueing(spec map[string]interface{}, labels map[string]string, queueing *config.QueueingSettings, namespace string, groupSpecs ...map[string]interface{}) error {
+	if queueing == nil {
+		return nil
+	}
+
+	switch queueing.Type {
+	case config.QueueingKueue:
+		queue := queueing.LocalQueueFor(namespace)
+		if queue == "" {
+			return fmt.Errorf("no Kueue LocalQueue configured for namespace %s", namespace)
+		}
+		labels[KueueQueueNameLabel] = queue
+		spec["suspend"] = true
+
+	case config.QueueingVolcano:
+		schedulerName := queueing.SchedulerName
+		if schedulerName == "" {
+			schedulerName = DefaultVolcanoScheduler
+		}
+		labels[RaySchedulerNameLabel] = DefaultVolcanoScheduler
+		if queueing.VolcanoQueue != "" {
+			labels[VolcanoQueueNameLabel] = queueing.VolcanoQueue
+		}
+		for _, groupSpec := range groupSpecs {
+			podSpec(groupSpec)["schedulerName"] = schedulerName
+		}
+	}
+
+	return nil
+}
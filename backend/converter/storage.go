@@ -0,0 +1,127 @@
+package converter
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/loiht2/ml-platform-training-job/backend/config"
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// Keys of the job's storage Secret. The MinIO client aliases hold the whole
+// MC_HOST_<alias> URL, with the credentials escaped for it.
+const (
+	storageSecretAccessKey = "accessKey"
+	storageSecretSecretKey = "secretKey"
+	storageSecretCodeHost  = "mc-host-code"
+)
+
+// ValidateStorageCredentials rejects requests that read or write object
+// storage from the pods when the platform has no credentials to hand them
+func ValidateStorageCredentials(req *models.TrainingJobRequest, credentials config.StorageCredentials) error {
+	if credentials.Configured() {
+		return nil
+	}
+	switch {
+	case req.StageInputData:
+		return fmt.Errorf("stageInputData requires the platform storage credentials, which are not configured")
+	case len(req.InputDataConfig) > 0:
+		return fmt.Errorf("inputDataConfig requires the platform storage credentials, which are not configured")
+	case IsCustomAlgorithm(req) && req.Algorithm.Code != nil && req.Algorithm.Code.S3 != nil:
+		return fmt.Errorf("algorithm.code.s3 requires the platform storage credentials, which are not configured")
+	case isS3ArtifactURI(req.OutputDataConfig.ArtifactURI):
+		return fmt.Errorf("s3:// artifact URIs require the platform storage credentials, which are not configured")
+	}
+	return nil
+}
+
+// StorageSecretName returns the name of the Secret holding a job's storage credentials
+func StorageSecretName(jobName string) string {
+	return fmt.Sprintf("%s-storage", jobName)
+}
+
+// CreateStorageSecret creates the Secret the job's pods read the platform
+// storage credentials from, propagated together with the RayJob. Jobs that do
+// not use object storage, or run without configured credentials, get none.
+func (c *Converter) CreateStorageSecret(req *models.TrainingJobRequest, jobID string) *corev1.Secret {
+	credentials := c.defaults.Storage
+	if !credentials.Configured() {
+		return nil
+	}
+
+	data := map[string]string{}
+	if usesStorageEnv(req) {
+		data[storageSecretAccessKey] = credentials.AccessKey
+		data[storageSecretSecretKey] = credentials.SecretKey
+	}
+	if IsCustomAlgorithm(req) && req.Algorithm.Code != nil && req.Algorithm.Code.S3 != nil {
+		// Validated beforehand, so the endpoint always parses here
+		data[storageSecretCodeHost], _ = minioHostURL(req.Algorithm.Code.S3.Endpoint, credentials)
+	}
+	if req.StageInputData {
+		for i, channel := range req.InputDataConfig {
+			data[stagingHostKey(i)], _ = minioHostURL(channel.Endpoint, credentials)
+		}
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	namespace := req.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      StorageSecretName(req.JobName),
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app":             req.JobName,
+				"training-job-id": jobID,
+			},
+		},
+		Type:       corev1.SecretTypeOpaque,
+		StringData: data,
+	}
+}
+
+// usesStorageEnv reports whether the training script gets the credentials as
+// env vars: to read the input channels or upload to s3:// artifact URIs
+func usesStorageEnv(req *models.TrainingJobRequest) bool {
+	return len(req.InputDataConfig) > 0 || isS3ArtifactURI(req.OutputDataConfig.ArtifactURI)
+}
+
+// stagingHostKey returns the storage Secret key of the MinIO alias staging a channel
+func stagingHostKey(index int) string {
+	return fmt.Sprintf("mc-host-src-%d", index)
+}
+
+// applyStorageEnv hands the credentials from the storage Secret to the Ray
+// containers, under the names the training scripts read
+func (c *Converter) applyStorageEnv(groupSpec map[string]interface{}, req *models.TrainingJobRequest) {
+	if !c.defaults.Storage.Configured() || !usesStorageEnv(req) {
+		return
+	}
+
+	secretName := StorageSecretName(req.JobName)
+	var env []map[string]interface{}
+	if len(req.InputDataConfig) > 0 {
+		env = append(env,
+			secretEnvVar("S3_ACCESS_KEY", secretName, storageSecretAccessKey),
+			secretEnvVar("S3_SECRET_KEY", secretName, storageSecretSecretKey),
+		)
+	}
+	if isS3ArtifactURI(req.OutputDataConfig.ArtifactURI) {
+		env = append(env,
+			secretEnvVar("AWS_ACCESS_KEY_ID", secretName, storageSecretAccessKey),
+			secretEnvVar("AWS_SECRET_ACCESS_KEY", secretName, storageSecretSecretKey),
+		)
+	}
+	appendEnv(groupSpec, env...)
+}
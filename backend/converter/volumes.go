@@ -0,0 +1,121 @@
+package converter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/loiht2/ml-platform-training-job/backend/config"
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+const (
+	SharedMemoryVolumeName = "dshm"
+	SharedMemoryMountPath  = "/dev/shm"
+	ScratchVolumeName      = "scratch"
+	ScratchMountPath       = "/home/ray/scratch"
+	StagedDataDir          = ScratchMountPath + "/data"
+)
+
+var channelNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ValidateDataStaging checks that every input channel can be staged from object storage
+func ValidateDataStaging(req *models.TrainingJobRequest) error {
+	if req.Resources.SharedMemoryGiB < 0 || req.Resources.ScratchVolumeGiB < 0 {
+		return fmt.Errorf("resources.sharedMemoryGiB and resources.scratchVolumeGiB must not be negative")
+	}
+	if !req.StageInputData {
+		return nil
+	}
+	if len(req.InputDataConfig) == 0 {
+		return fmt.Errorf("stageInputData requires at least one input channel")
+	}
+	for i, channel := range req.InputDataConfig {
+		if channel.Endpoint == "" || bucketName(channel.Bucket) == "" {
+			return fmt.Errorf("inputDataConfig[%d] needs an endpoint and bucket to be staged", i)
+		}
+		if _, err := minioHostURL(channel.Endpoint, config.StorageCredentials{}); err != nil {
+			return fmt.Errorf("inputDataConfig[%d]: %w", i, err)
+		}
+		if channel.ChannelName != "" && !channelNamePattern.MatchString(channel.ChannelName) {
+			return fmt.Errorf("inputDataConfig[%d].channelName may only contain letters, digits, '-' and '_'", i)
+		}
+	}
+	return nil
+}
+
+// applyPodVolumes adds the shared-memory and scratch volumes and, when requested,
+// init containers that stage the input channels onto the scratch volume
+func (c *Converter) applyPodVolumes(groupSpec map[string]interface{}, req *models.TrainingJobRequest) {
+	// Memory-backed /dev/shm for Ray's object store and data loaders; it counts
+	// against the container memory limit
+	if size := req.Resources.SharedMemoryGiB; size > 0 {
+		appendVolumes(groupSpec, emptyDirVolume(SharedMemoryVolumeName, "Memory", fmt.Sprintf("%dGi", size)))
+		appendVolumeMounts(groupSpec, volumeMount(SharedMemoryVolumeName, SharedMemoryMountPath))
+	}
+
+	// Node-local scratch space, also used as the target of data staging
+	if req.Resources.ScratchVolumeGiB > 0 || req.StageInputData {
+		sizeLimit := ""
+		if req.Resources.ScratchVolumeGiB > 0 {
+			sizeLimit = fmt.Sprintf("%dGi", req.Resources.ScratchVolumeGiB)
+		}
+		appendVolumes(groupSpec, emptyDirVolume(ScratchVolumeName, "", sizeLimit))
+		appendVolumeMounts(groupSpec, volumeMount(ScratchVolumeName, ScratchMountPath))
+	}
+
+	if req.StageInputData {
+		for i, channel := range req.InputDataConfig {
+			appendInitContainers(groupSpec, stageDataContainer(i, channel, StorageSecretName(req.JobName)))
+		}
+	}
+}
+
+// stageDataContainer copies one input channel from object storage to the
+// scratch volume, with the MinIO alias read from the job's storage Secret
+func stageDataContainer(index int, channel models.InputDataConfig, secretName string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":  fmt.Sprintf("stage-data-%d", index),
+		"image": DefaultMinioClientImage,
+		"args": []string{
+			"cp", "--recursive", "--quiet",
+			fmt.Sprintf("src/%s/%s", bucketName(channel.Bucket), strings.TrimPrefix(channel.Prefix, "/")),
+			stagedChannelDir(index, channel) + "/",
+		},
+		"env": []interface{}{
+			secretEnvVar("MC_HOST_src", secretName, stagingHostKey(index)),
+		},
+		"volumeMounts": []interface{}{volumeMount(ScratchVolumeName, ScratchMountPath)},
+	}
+}
+
+// stagedChannelDir returns the local directory a channel is staged into
+func stagedChannelDir(index int, channel models.InputDataConfig) string {
+	name := channel.ChannelName
+	if name == "" {
+		name = fmt.Sprintf("channel-%d", index)
+	}
+	return StagedDataDir + "/" + name
+}
+
+// appendStagedDataEnv tells the training script where the staged channels are
+func (c *Converter) appendStagedDataEnv(sb *strings.Builder, req *models.TrainingJobRequest) {
+	if !req.StageInputData || len(req.InputDataConfig) == 0 {
+		return
+	}
+
+	sb.WriteString("\n  # ==== Staged Input Data ====\n")
+	sb.WriteString(fmt.Sprintf("  LOCAL_DATA_DIR: \"%s\"\n", StagedDataDir))
+	sb.WriteString(fmt.Sprintf("  LOCAL_TRAIN_PATH: \"%s\"\n", stagedChannelDir(0, req.InputDataConfig[0])))
+	if len(req.InputDataConfig) > 1 {
+		sb.WriteString(fmt.Sprintf("  LOCAL_VAL_PATH: \"%s\"\n", stagedChannelDir(1, req.InputDataConfig[1])))
+	}
+}
+
+// bucketName strips URI-style prefixes such as s3:// or storage:// from a bucket field
+func bucketName(bucket string) string {
+	if i := strings.Index(bucket, "://"); i >= 0 {
+		bucket = bucket[i+3:]
+	}
+	return strings.Trim(bucket, "/")
+}
@@ -0,0 +1,146 @@
+package converter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/loiht2/ml-platform-training-job/backend/config"
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// testGroupSpec returns a Ray group spec with a single container
+func testGroupSpec() map[string]interface{} {
+	return map[string]interface{}{
+		"template": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{"name": "ray-worker"}},
+			},
+		},
+	}
+}
+
+func TestValidateDataStaging(t *testing.T) {
+	channel := models.InputDataConfig{ChannelName: "train", Endpoint: "minio.local:9000", Bucket: "s3://data/", Prefix: "train/"}
+	tests := []struct {
+		name    string
+		req     models.TrainingJobRequest
+		wantErr bool
+	}{
+		{"no staging", models.TrainingJobRequest{}, false},
+		{"negative shared memory", models.TrainingJobRequest{Resources: models.Resources{SharedMemoryGiB: -1}}, true},
+		{"negative scratch", models.TrainingJobRequest{Resources: models.Resources{ScratchVolumeGiB: -1}}, true},
+		{"staged channel", models.TrainingJobRequest{StageInputData: true, InputDataConfig: []models.InputDataConfig{channel}}, false},
+		{"no channels", models.TrainingJobRequest{StageInputData: true}, true},
+		{"no endpoint", models.TrainingJobRequest{StageInputData: true, InputDataConfig: []models.InputDataConfig{{Bucket: "data"}}}, true},
+		{"no bucket", models.TrainingJobRequest{StageInputData: true, InputDataConfig: []models.InputDataConfig{{Endpoint: "minio.local:9000", Bucket: "s3://"}}}, true},
+		{"invalid endpoint", models.TrainingJobRequest{StageInputData: true, InputDataConfig: []models.InputDataConfig{{Endpoint: "http://", Bucket: "data"}}}, true},
+		{"channel name with a path", models.TrainingJobRequest{StageInputData: true, InputDataConfig: []models.InputDataConfig{
+			{ChannelName: "../train", Endpoint: "minio.local:9000", Bucket: "data"},
+		}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateDataStaging(&tt.req); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDataStaging() error = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyPodVolumes(t *testing.T) {
+	tests := []struct {
+		name           string
+		req            models.TrainingJobRequest
+		wantVolumes    []interface{}
+		wantMounts     []interface{}
+		wantContainers []string
+	}{
+		{
+			name: "none requested",
+		},
+		{
+			name: "shared memory and scratch",
+			req:  models.TrainingJobRequest{Resources: models.Resources{SharedMemoryGiB: 2, ScratchVolumeGiB: 50}},
+			wantVolumes: []interface{}{
+				emptyDirVolume(SharedMemoryVolumeName, "Memory", "2Gi"),
+				emptyDirVolume(ScratchVolumeName, "", "50Gi"),
+			},
+			wantMounts: []interface{}{
+				volumeMount(SharedMemoryVolumeName, SharedMemoryMountPath),
+				volumeMount(ScratchVolumeName, ScratchMountPath),
+			},
+		},
+		{
+			name: "staging without a scratch size",
+			req: models.TrainingJobRequest{
+				JobName:        "churn",
+				StageInputData: true,
+				InputDataConfig: []models.InputDataConfig{
+					{ChannelName: "train", Endpoint: "minio.local:9000", Bucket: "data", Prefix: "/train/"},
+					{Endpoint: "minio.local:9000", Bucket: "data", Prefix: "val/"},
+				},
+			},
+			wantVolumes:    []interface{}{emptyDirVolume(ScratchVolumeName, "", "")},
+			wantMounts:     []interface{}{volumeMount(ScratchVolumeName, ScratchMountPath)},
+			wantContainers: []string{"stage-data-0", "stage-data-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := testGroupSpec()
+			NewConverter(&config.Settings{}).applyPodVolumes(group, &tt.req)
+
+			pod := podSpec(group)
+			volumes, _ := pod["volumes"].([]interface{})
+			if !reflect.DeepEqual(volumes, tt.wantVolumes) {
+				t.Errorf("volumes = %v, want %v", volumes, tt.wantVolumes)
+			}
+			mounts, _ := pod["containers"].([]interface{})[0].(map[string]interface{})["volumeMounts"].([]interface{})
+			if !reflect.DeepEqual(mounts, tt.wantMounts) {
+				t.Errorf("volume mounts = %v, want %v", mounts, tt.wantMounts)
+			}
+			initContainers, _ := pod["initContainers"].([]interface{})
+			var names []string
+			for _, c := range initContainers {
+				names = append(names, c.(map[string]interface{})["name"].(string))
+			}
+			if !reflect.DeepEqual(names, tt.wantContainers) {
+				t.Errorf("init containers = %v, want %v", names, tt.wantContainers)
+			}
+		})
+	}
+}
+
+func TestStageDataContainer(t *testing.T) {
+	channel := models.InputDataConfig{ChannelName: "train", Endpoint: "minio.local:9000", Bucket: "s3://data/", Prefix: "/train/"}
+	container := stageDataContainer(0, channel, "churn-storage")
+
+	wantArgs := []string{"cp", "--recursive", "--quiet", "src/data/train/", StagedDataDir + "/train/"}
+	if args := container["args"]; !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %v, want %v", args, wantArgs)
+	}
+	wantEnv := []interface{}{secretEnvVar("MC_HOST_src", "churn-storage", "mc-host-src-0")}
+	if env := container["env"]; !reflect.DeepEqual(env, wantEnv) {
+		t.Errorf("env = %v, want %v", env, wantEnv)
+	}
+
+	if dir := stagedChannelDir(3, models.InputDataConfig{}); dir != StagedDataDir+"/channel-3" {
+		t.Errorf("unnamed channel staged into %q, want %q", dir, StagedDataDir+"/channel-3")
+	}
+}
+
+func TestResourceDemandCountsSharedMemory(t *testing.T) {
+	c := NewConverter(&config.Settings{})
+	req := &models.TrainingJobRequest{Resources: models.Resources{
+		InstanceResources: models.InstanceResources{CPUCores: 4, MemoryGiB: 16, GPUCount: 1},
+		InstanceCount:     2,
+		SharedMemoryGiB:   8,
+	}}
+
+	want := models.ResourceAmounts{CPUCores: 12, MemoryGiB: 72, GPUs: 2, Pods: 3}
+	if got := c.ResourceDemand(req); got != want {
+		t.Errorf("ResourceDemand() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/loiht2/ml-platform-training-job/backend/config"
+	"github.com/loiht2/ml-platform-training-job/backend/karmada"
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// clusterSelectionTimeout bounds how long job creation waits for the cluster list
+const clusterSelectionTimeout = 10 * time.Second
+
+// clusterChoice is the cluster picked for a job without target clusters, the
+// clusters it may still move to, and the queueing integration they share
+type clusterChoice struct {
+	preferred string
+	fallback  []string
+	queueing  *config.QueueingSettings
+}
+
+// clusterFit is a ready cluster with the capacity a job is checked against:
+// what is free now, or everything allocatable when the cluster queues jobs
+// until capacity frees up
+type clusterFit struct {
+	cluster models.ClusterInfo
+	free    models.ResourceAmounts
+	queued  bool
+}
+
+// demandResource is one resource of a job's demand, as named in error messages
+type demandResource struct {
+	unit   string
+	amount func(models.ResourceAmounts) float64
+}
+
+var demandResources = []demandResource{
+	{"GPUs", func(r models.ResourceAmounts) float64 { return float64(r.GPUs) }},
+	{"CPU cores", func(r models.ResourceAmounts) float64 { return r.CPUCores }},
+	{"GiB of memory", func(r models.ResourceAmounts) float64 { return r.MemoryGiB }},
+	{"pods", func(r models.ResourceAmounts) float64 { return float64(r.Pods) }},
+}
+
+// selectCluster checks the job's resource demand against the free capacity of
+// the clusters it may run in. Jobs naming their clusters, by target clusters
+// or weights, are rejected when one of them cannot fit the job. Other jobs get
+// the fitting cluster the selection strategy prefers, or are rejected when no
+// cluster fits. Clusters that have not reported their capacity are left to
+// Karmada, as is everything when the cluster list cannot be read.
+func (h *Handler) selectCluster(karmadaClient karmada.Interface, req *models.TrainingJobRequest) (*clusterChoice, *apiError) {
+	selection := h.settings().ClusterSelectionFor()
+	if selection.Disabled {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), clusterSelectionTimeout)
+	defer cancel()
+
+	clusters, err := karmadaClient.ListMemberClusters(ctx)
+	if err != nil {
+		log.Printf("Warning: skipping capacity check for job %s: %v", req.JobName, err)
+		return nil, nil
+	}
+
+	demand := h.converter().ResourceDemand(req)
+	placement := h.settings().PlacementFor(req.Placement)
+
+	// The job runs whole in each cluster it names
+	named := req.TargetClusters
+	if len(named) == 0 && placement != nil {
+		named = placement.WeightedClusters()
+	}
+	if len(named) > 0 {
+		return nil, h.checkNamedClusters(named, clusters, demand)
+	}
+	if placement != nil && len(placement.SpreadConstraints) > 0 {
+		// The spread constraints decide how many clusters run the job
+		return nil, nil
+	}
+
+	var matching []models.ClusterInfo
+	for _, cluster := range clusters {
+		if placement == nil || matchesLabels(cluster.Labels, placement.ClusterLabels) {
+			matching = append(matching, cluster)
+		}
+	}
+
+	candidates := h.clusterFits(matching)
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	var fitting []clusterFit
+	for _, candidate := range candidates {
+		if fits(demand, candidate) {
+			fitting = append(fitting, candidate)
+		}
+	}
+	if len(fitting) == 0 {
+		return nil, &apiError{http.StatusUnprocessableEntity, gin.H{
+			"error":   "No cluster can fit the job",
+			"details": describeShortage(demand, candidates),
+		}}
+	}
+
+	// The job may move to the other ready clusters that fit it, best first,
+	// as long as they queue it the same way: one RayJob template is
+	// propagated to all of them
+	rankClusters(fitting, demand, selection)
+	settings := h.settings()
+	choice := &clusterChoice{preferred: fitting[0].cluster.Name}
+	choice.queueing, _ = settings.QueueingFor([]string{choice.preferred})
+	for _, candidate := range fitting[1:] {
+		if _, err := settings.QueueingFor([]string{choice.preferred, candidate.cluster.Name}); err != nil {
+			continue
+		}
+		choice.fallback = append(choice.fallback, candidate.cluster.Name)
+	}
+	log.Printf("Selected cluster %s for job %s (strategy %s)", choice.preferred, req.JobName, selection.Strategy)
+	return choice, nil
+}
+
+// checkNamedClusters rejects a job when one of the clusters it names cannot fit it
+func (h *Handler) checkNamedClusters(names []string, clusters []models.ClusterInfo, demand models.ResourceAmounts) *apiError {
+	byName := make(map[string]models.ClusterInfo, len(clusters))
+	for _, cluster := range clusters {
+		byName[cluster.Name] = cluster
+	}
+
+	var named []models.ClusterInfo
+	for _, name := range names {
+		cluster, ok := byName[name]
+		if !ok {
+			return &apiError{http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Cluster %s is not registered in Karmada", name)}}
+		}
+		named = append(named, cluster)
+	}
+
+	for _, candidate := range h.clusterFits(named) {
+		if !fits(demand, candidate) {
+			return &apiError{http.StatusUnprocessableEntity, gin.H{
+				"error":   fmt.Sprintf("Cluster %s cannot fit the job", candidate.cluster.Name),
+				"details": describeShortage(demand, []clusterFit{candidate}),
+			}}
+		}
+	}
+	return nil
+}
+
+// clusterFits returns the ready clusters that reported their capacity
+func (h *Handler) clusterFits(clusters []models.ClusterInfo) []clusterFit {
+	var candidates []clusterFit
+	for _, cluster := range clusters {
+		if !cluster.Ready || cluster.Capacity == nil {
+			continue
+		}
+		candidate := clusterFit{cluster: cluster, free: cluster.Capacity.Available}
+		if queueing, _ := h.settings().QueueingFor([]string{cluster.Name}); queueing != nil {
+			candidate.free = cluster.Capacity.Allocatable
+			candidate.queued = true
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+// fits reports whether a cluster has enough of every resource the job requests.
+// Clusters that do not report a pod limit are not checked for pods.
+func fits(demand models.ResourceAmounts, candidate clusterFit) bool {
+	for _, resource := range demandResources {
+		if resource.unit == "pods" && candidate.cluster.Capacity.Allocatable.Pods == 0 {
+			continue
+		}
+		if resource.amount(demand) > resource.amount(candidate.free) {
+			return false
+		}
+	}
+	return true
+}
+
+// describeShortage explains why the candidates cannot run the job, naming
+// each resource no candidate has enough of, e.g. "no cluster can fit 8 GPUs"
+func describeShortage(demand models.ResourceAmounts, candidates []clusterFit) string {
+	var shortages []string
+	for _, resource := range demandResources {
+		needed := resource.amount(demand)
+		if needed == 0 {
+			continue
+		}
+
+		most := candidates[0]
+		for _, candidate := range candidates[1:] {
+			if resource.amount(candidate.free) > resource.amount(most.free) {
+				most = candidate
+			}
+		}
+		if resource.unit == "pods" && most.cluster.Capacity.Allocatable.Pods == 0 {
+			continue
+		}
+		if needed <= resource.amount(most.free) {
+			continue
+		}
+		kind := "free"
+		if most.queued {
+			kind = "allocatable"
+		}
+		if len(candidates) == 1 {
+			shortages = append(shortages, fmt.Sprintf("%g %s (%g %s)", needed, resource.unit, resource.amount(most.free), kind))
+		} else {
+			shortages = append(shortages, fmt.Sprintf("%g %s (most %s: %g in %s)",
+				needed, resource.unit, kind, resource.amount(most.free), most.cluster.Name))
+		}
+	}
+
+	if len(shortages) == 0 {
+		return fmt.Sprintf("no single cluster can fit %d GPUs, %g CPU cores, %g GiB of memory and %d pods at once",
+			demand.GPUs, demand.CPUCores, demand.MemoryGiB, demand.Pods)
+	}
+	if len(candidates) == 1 {
+		return fmt.Sprintf("cluster %s cannot fit %s", candidates[0].cluster.Name, strings.Join(shortages, ", "))
+	}
+	return fmt.Sprintf("no cluster can fit %s", strings.Join(shortages, ", "))
+}
+
+// rankClusters orders fitting clusters by the selection strategy, best first.
+// most-free prefers the most headroom left after the job in its scarcest
+// resource (GPUs for GPU jobs, otherwise CPU cores), bin-pack the least, and
+// prefer-region the preferred regions in order, then the most headroom.
+func rankClusters(candidates []clusterFit, demand models.ResourceAmounts, selection config.ClusterSelectionSettings) {
+	headroom := func(candidate clusterFit) float64 {
+		if demand.GPUs > 0 {
+			return float64(candidate.free.GPUs - demand.GPUs)
+		}
+		return candidate.free.CPUCores - demand.CPUCores
+	}
+	regionRank := func(candidate clusterFit) int {
+		for i, region := range selection.PreferredRegions {
+			if candidate.cluster.Region == region {
+				return i
+			}
+		}
+		return len(selection.PreferredRegions)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if selection.Strategy == config.SelectionPreferRegion && regionRank(a) != regionRank(b) {
+			return regionRank(a) < regionRank(b)
+		}
+		if headroom(a) != headroom(b) {
+			if selection.Strategy == config.SelectionBinPack {
+				return headroom(a) < headroom(b)
+			}
+			return headroom(a) > headroom(b)
+		}
+		return a.cluster.Name < b.cluster.Name
+	})
+}
+
+// matchesLabels reports whether a cluster has all the required labels
+func matchesLabels(labels, required map[string]string) bool {
+	for key, value := range required {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
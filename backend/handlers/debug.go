@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetActiveConfig handles GET /api/v1/debug/config. It reports the version and
+// source of the active platform settings, and the settings without credentials.
+func (h *Handler) GetActiveConfig(c *gin.Context) {
+	settings, err := h.settings().Redacted()
+	if err != nil {
+		log.Printf("Failed to redact settings: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read settings"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"version":  h.cfg.SettingsVersion(),
+		"settings": settings,
+	})
+}
+
+// GetMonitorStats handles GET /api/v1/debug/monitor. It reports the load of
+// the job monitor: its backlog, retrying jobs and reconcile durations. Only
+// the leader runs the monitor; other replicas answer 503 naming the leader.
+func (h *Handler) GetMonitorStats(c *gin.Context) {
+	stats, ok := h.monitorStats()
+	if !ok {
+		status := h.elector.Status()
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":  fmt.Sprintf("The job monitor runs on the leader %q", status.Leader),
+			"leader": status,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetLeaderStatus handles GET /api/v1/debug/leader. It reports which replica
+// leads and runs the job monitor, and whether it is this one.
+func (h *Handler) GetLeaderStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.elector.Status())
+}
@@ -2,37 +2,83 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/loiht2/ml-platform-training-job/backend/config"
 	"github.com/loiht2/ml-platform-training-job/backend/converter"
+	"github.com/loiht2/ml-platform-training-job/backend/jobstatus"
 	"github.com/loiht2/ml-platform-training-job/backend/karmada"
+	"github.com/loiht2/ml-platform-training-job/backend/leader"
 	"github.com/loiht2/ml-platform-training-job/backend/models"
 	"github.com/loiht2/ml-platform-training-job/backend/repository"
 )
 
+// jobDeletionTimeout bounds how long job deletion waits for the member cluster copies to be gone
+const jobDeletionTimeout = 60 * time.Second
+
 // Handler handles HTTP requests
 type Handler struct {
-	cfg       *config.Config
-	repo      *repository.Repository
-	converter *converter.Converter
-	karmada   *karmada.Client
+	cfg         *config.Config
+	repo        repository.Store
+	federations *karmada.Federations
+	elector     *leader.Elector
+	// monitorStats returns the load of the running job monitor; ok is false
+	// unless this replica leads and runs it
+	monitorStats func() (stats models.MonitorStats, ok bool)
 }
 
 // NewHandler creates a new handler instance
-func NewHandler(cfg *config.Config, repo *repository.Repository) *Handler {
+func NewHandler(cfg *config.Config, repo repository.Store, federations *karmada.Federations, elector *leader.Elector, monitorStats func() (models.MonitorStats, bool)) *Handler {
 	return &Handler{
-		cfg:       cfg,
-		repo:      repo,
-		converter: converter.NewConverter(),
-		karmada:   karmada.NewClient(cfg.KarmadaClient, cfg.KarmadaK8sClient),
+		cfg:          cfg,
+		repo:         repo,
+		federations:  federations,
+		elector:      elector,
+		monitorStats: monitorStats,
+	}
+}
+
+// settings returns the active platform settings
+func (h *Handler) settings() *config.Settings {
+	return h.cfg.CurrentSettings()
+}
+
+// converter returns a converter using the active platform settings
+func (h *Handler) converter() *converter.Converter {
+	return converter.NewConverter(h.settings())
+}
+
+// jobKarmada returns the Karmada client of the federation a job runs in
+func (h *Handler) jobKarmada(job *config.TrainingJob) (karmada.Interface, *apiError) {
+	client, err := h.federations.Get(job.Federation)
+	if err != nil {
+		log.Printf("Job %s: %v", job.ID, err)
+		return nil, &apiError{http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Federation of job %s is not configured: %v", job.ID, err)}}
 	}
+	return client, nil
+}
+
+// queryKarmada returns the Karmada client of the federation in the "federation" query parameter, or the default one
+func (h *Handler) queryKarmada(c *gin.Context) (karmada.Interface, *apiError) {
+	client, err := h.federations.Get(c.Query("federation"))
+	if err != nil {
+		return nil, &apiError{http.StatusNotFound, gin.H{"error": fmt.Sprintf("Federation %s not found", c.Query("federation"))}}
+	}
+	return client, nil
 }
 
 // CreateTrainingJob handles POST /api/v1/jobs
@@ -47,6 +93,95 @@ func (h *Handler) CreateTrainingJob(c *gin.Context) {
 		return
 	}
 
+	response, err := h.submitTrainingJob(&req)
+	if err != nil {
+		err.respond(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// ImportRayJob handles POST /api/v1/jobs/import
+// It maps an existing RayJob manifest to a training job request and, when
+// asked to, submits it like any other job
+func (h *Handler) ImportRayJob(c *gin.Context) {
+	var body models.ImportRayJobRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		log.Printf("Invalid import payload: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	req, unmapped, err := h.converter().ImportRayJob([]byte(body.Manifest))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid RayJob manifest",
+			"details": err.Error(),
+		})
+		return
+	}
+	if len(body.TargetClusters) > 0 {
+		req.TargetClusters = body.TargetClusters
+	}
+	if body.Federation != "" {
+		req.Federation = body.Federation
+	}
+	if unmapped == nil {
+		unmapped = []string{}
+	}
+
+	response := models.ImportRayJobResponse{Request: req, Unmapped: unmapped}
+	if !body.Submit {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	job, apiErr := h.submitTrainingJob(req)
+	if apiErr != nil {
+		apiErr.body["unmapped"] = unmapped
+		apiErr.respond(c)
+		return
+	}
+	log.Printf("Imported RayJob %s as job %s (%d unmapped fields)", req.JobName, job.ID, len(unmapped))
+
+	response.Job = job
+	c.JSON(http.StatusCreated, response)
+}
+
+// apiError is a failed request together with the HTTP response describing it
+type apiError struct {
+	status int
+	body   gin.H
+}
+
+func (e *apiError) respond(c *gin.Context) {
+	c.JSON(e.status, e.body)
+}
+
+// statusForError maps an error from the Kubernetes or Karmada API to the HTTP status reported to clients
+func statusForError(err error) int {
+	switch {
+	case apierrors.IsNotFound(err), meta.IsNoMatchError(err):
+		return http.StatusNotFound
+	case apierrors.IsAlreadyExists(err), apierrors.IsConflict(err):
+		return http.StatusConflict
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return http.StatusBadRequest
+	case apierrors.IsForbidden(err):
+		return http.StatusForbidden
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), apierrors.IsTooManyRequests(err), apierrors.IsServiceUnavailable(err):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// submitTrainingJob validates a request, records it and applies it to Karmada
+func (h *Handler) submitTrainingJob(req *models.TrainingJobRequest) (*models.TrainingJobResponse, *apiError) {
 	// Set default namespace
 	if req.Namespace == "" {
 		req.Namespace = "default"
@@ -54,8 +189,39 @@ func (h *Handler) CreateTrainingJob(c *gin.Context) {
 
 	// Validate job name
 	if req.JobName == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Job name is required"})
-		return
+		return nil, &apiError{http.StatusBadRequest, gin.H{"error": "Job name is required"}}
+	}
+
+	if err := validateTrainingJobRequest(req, h.settings()); err != nil {
+		return nil, &apiError{http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		}}
+	}
+
+	// Determine job type from algorithm
+	// For XGBoost and similar algorithms, or custom code, create RayJob
+	jobType := req.Algorithm.AlgorithmName
+	if jobType != "xgboost" && jobType != "ray" && !converter.IsCustomAlgorithm(req) {
+		return nil, &apiError{http.StatusBadRequest, gin.H{"error": "Unsupported algorithm. Only 'xgboost', 'ray' and custom algorithms are supported currently."}}
+	}
+
+	// Route the job to the control plane of its federation
+	if req.Federation == "" {
+		req.Federation = h.settings().FederationFor(req.Namespace)
+	}
+	karmadaClient, err := h.federations.Get(req.Federation)
+	if err != nil {
+		return nil, &apiError{http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		}}
+	}
+
+	// Check the job fits where it may run, and pick a cluster if it names none
+	choice, apiErr := h.selectCluster(karmadaClient, req)
+	if apiErr != nil {
+		return nil, apiErr
 	}
 
 	// Generate unique job ID
@@ -63,14 +229,13 @@ func (h *Handler) CreateTrainingJob(c *gin.Context) {
 	log.Printf("Creating training job: %s (ID: %s)", req.JobName, jobID)
 
 	// Save to database
-	dbJob, err := h.repo.CreateTrainingJob(&req, jobID)
+	dbJob, err := h.repo.CreateTrainingJob(req, jobID)
 	if err != nil {
 		log.Printf("Failed to create training job in database: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
+		return nil, &apiError{http.StatusInternalServerError, gin.H{
 			"error":   "Failed to create training job in database",
 			"details": err.Error(),
-		})
-		return
+		}}
 	}
 
 	// Convert to K8s resource and apply to Karmada
@@ -78,56 +243,178 @@ func (h *Handler) CreateTrainingJob(c *gin.Context) {
 	defer cancel()
 
 	var applyErr error
-	
-	// Determine job type from algorithm
-	jobType := req.Algorithm.AlgorithmName
-	
-	// For XGBoost and similar algorithms, create RayJob
-	if jobType == "xgboost" || jobType == "ray" {
-		// Create PVC first (optional, only if needed)
-		if req.Resources.VolumeSizeGB > 0 && req.PVCName == "" {
-			pvc := h.converter.CreatePVC(&req, jobID)
-			if err := h.karmada.CreatePVC(ctx, pvc); err != nil {
-				log.Printf("Warning: Failed to create PVC: %v", err)
-				// Continue anyway - PVC might already exist
+	var resources []models.ResourceRef
+
+	// Jobs with their own PVC propagate it together with the RayJob
+	jobConverter := h.converter()
+	ownsPVC := jobConverter.OwnsPVC(req)
+	opts := h.propagationOptions(req, choice)
+
+	// Create PVC first (optional, only if needed)
+	if ownsPVC {
+		pvc := jobConverter.CreatePVC(req, jobID)
+		ref, err := karmadaClient.CreatePVC(ctx, pvc)
+		if err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				log.Printf("Warning: PVC %s/%s already exists, reusing it", pvc.Namespace, pvc.Name)
+			} else {
+				applyErr = fmt.Errorf("failed to create PVC: %w", err)
 			}
+		} else {
+			resources = append(resources, ref)
 		}
-		
-		// Create RayJob using new converter
-		rayJob, err := h.converter.ConvertToRayJobV2(&req, jobID)
+		opts.Dependencies = append(opts.Dependencies, policyv1alpha1.ResourceSelector{
+			APIVersion: "v1",
+			Kind:       "PersistentVolumeClaim",
+			Name:       pvc.Name,
+		})
+	}
+
+	// Storage credentials reach the pods through a Secret
+	if secret := jobConverter.CreateStorageSecret(req, jobID); secret != nil && applyErr == nil {
+		ref, err := karmadaClient.CreateSecret(ctx, secret)
 		if err != nil {
-			applyErr = fmt.Errorf("failed to convert to RayJob: %w", err)
+			applyErr = fmt.Errorf("failed to create storage Secret: %w", err)
 		} else {
-			applyErr = h.karmada.CreateRayJobWithPropagationPolicy(ctx, rayJob, req.TargetClusters)
+			resources = append(resources, ref)
 		}
-	} else {
-		// For other algorithms, create standard Kubernetes Job
-		// Note: This uses old format, may need updating
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported algorithm. Only 'xgboost' and 'ray' are supported currently."})
-		return
+		opts.Dependencies = append(opts.Dependencies, policyv1alpha1.ResourceSelector{
+			APIVersion: "v1",
+			Kind:       "Secret",
+			Name:       secret.Name,
+		})
+	}
+
+	// Inline scripts of custom algorithms are mounted from a ConfigMap
+	if configMap := jobConverter.CreateCodeConfigMap(req, jobID); configMap != nil && applyErr == nil {
+		ref, err := karmadaClient.CreateConfigMap(ctx, configMap)
+		if err != nil {
+			applyErr = fmt.Errorf("failed to create code ConfigMap: %w", err)
+		} else {
+			resources = append(resources, ref)
+		}
+		opts.Dependencies = append(opts.Dependencies, policyv1alpha1.ResourceSelector{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Name:       configMap.Name,
+		})
+	}
+
+	// Create RayJob using new converter, queued as the clusters it runs in are set up for
+	queueing, err := h.jobQueueing(req, choice)
+	if err != nil && applyErr == nil {
+		applyErr = err
+	}
+	var rayJob map[string]interface{}
+	if applyErr == nil {
+		rayJob, err = jobConverter.ConvertToRayJobWithQueueing(req, jobID, queueing)
+		if err != nil {
+			applyErr = fmt.Errorf("failed to convert to RayJob: %w", err)
+		}
+	}
+	if applyErr == nil {
+		applyErr = h.prepareQueueing(ctx, karmadaClient, queueing)
+	}
+	if applyErr == nil {
+		var created []models.ResourceRef
+		created, applyErr = karmadaClient.CreateRayJobWithPropagationPolicy(ctx, rayJob, opts)
+		resources = append(resources, created...)
+	}
+
+	// Record what was created, also for failed jobs, so deleting the job removes it
+	if err := h.repo.SetJobResources(jobID, resources); err != nil {
+		log.Printf("Failed to record resources of job %s: %v", jobID, err)
 	}
 
 	if applyErr != nil {
 		log.Printf("Failed to apply job to Karmada: %v", applyErr)
 		// Update database status
 		h.repo.UpdateTrainingJobStatus(jobID, "Failed", applyErr.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to apply job: %v", applyErr)})
-		return
+		return nil, &apiError{http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to apply job: %v", applyErr)}}
 	}
 
-	// Update status to Running
-	h.repo.UpdateTrainingJobStatus(jobID, "Running", "Job submitted to Karmada")
+	// The job stays Pending until the job monitor sees its RayJob run with the
+	// PVC usable in its clusters. Claims of WaitForFirstConsumer storage
+	// classes only bind once the pods are scheduled, so creation does not wait.
+	status, message := "Pending", "Job submitted to Karmada"
+	if choice != nil {
+		message = fmt.Sprintf("Job submitted to Karmada, preferring cluster %s", choice.preferred)
+	}
+	h.repo.UpdateTrainingJobStatus(jobID, status, message)
 
 	// Convert to response
-	response, err := h.repo.ToResponse(dbJob)
+	response, err := repository.ToResponse(dbJob)
 	if err != nil {
 		log.Printf("Failed to convert to response: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create response"})
-		return
+		return nil, &apiError{http.StatusInternalServerError, gin.H{"error": "Failed to create response"}}
 	}
-	response.Status = "Running"
+	response.Status = status
+	response.Message = message
+	response.Resources = resources
 
-	c.JSON(http.StatusCreated, response)
+	return response, nil
+}
+
+// propagationOptions builds the Karmada propagation options for a request and
+// the cluster selected for it, if any
+func (h *Handler) propagationOptions(req *models.TrainingJobRequest, choice *clusterChoice) karmada.PropagationOptions {
+	settings := h.settings()
+	opts := karmada.PropagationOptions{
+		TargetClusters: req.TargetClusters,
+		Overrides:      settings.OverridesFor(req.TargetClusters, req.ClusterOverrides),
+		Placement:      settings.PlacementFor(req.Placement),
+		Failover:       h.failoverOptions(req),
+	}
+	if choice != nil {
+		opts.PreferredCluster = choice.preferred
+		opts.FallbackClusters = choice.fallback
+	}
+
+	if mapping := settings.PriorityClassFor(req.Priority); mapping != nil {
+		opts.Priority = mapping.PolicyPriority
+		opts.Preemption = policyv1alpha1.PreemptionBehavior(mapping.Preemption)
+	}
+
+	return opts
+}
+
+// failoverOptions applies the failover settings and the job's opt-out to the platform defaults
+func (h *Handler) failoverOptions(req *models.TrainingJobRequest) *karmada.FailoverOptions {
+	opts := karmada.DefaultFailoverOptions()
+	opts.Disabled = req.DisableFailover
+
+	settings := h.settings()
+	if settings == nil || settings.Failover == nil {
+		return opts
+	}
+	failover := settings.Failover
+	if failover.ClusterTolerationSeconds != nil {
+		opts.ClusterTolerationSeconds = *failover.ClusterTolerationSeconds
+	}
+	if failover.UnhealthyTolerationSeconds != nil {
+		opts.UnhealthyTolerationSeconds = *failover.UnhealthyTolerationSeconds
+	}
+	if failover.GracePeriodSeconds != nil {
+		opts.GracePeriodSeconds = *failover.GracePeriodSeconds
+	}
+	return opts
+}
+
+// jobQueueing returns the queueing integration of the clusters a job runs in:
+// its target clusters, or the cluster picked for it
+func (h *Handler) jobQueueing(req *models.TrainingJobRequest, choice *clusterChoice) (*config.QueueingSettings, error) {
+	if choice != nil {
+		return choice.queueing, nil
+	}
+	return h.settings().QueueingFor(req.TargetClusters)
+}
+
+// prepareQueueing makes sure Karmada can hand queued jobs over to Kueue in the member clusters
+func (h *Handler) prepareQueueing(ctx context.Context, karmadaClient karmada.Interface, queueing *config.QueueingSettings) error {
+	if queueing != nil && queueing.Type == config.QueueingKueue {
+		return karmadaClient.EnsureRayJobSuspendRetention(ctx)
+	}
+	return nil
 }
 
 // ListTrainingJobs handles GET /api/v1/jobs
@@ -143,7 +430,7 @@ func (h *Handler) ListTrainingJobs(c *gin.Context) {
 
 	responses := make([]*models.TrainingJobResponse, 0, len(jobs))
 	for i := range jobs {
-		response, err := h.repo.ToResponse(&jobs[i])
+		response, err := repository.ToResponse(&jobs[i])
 		if err != nil {
 			log.Printf("Failed to convert job to response: %v", err)
 			continue
@@ -165,7 +452,7 @@ func (h *Handler) GetTrainingJob(c *gin.Context) {
 		return
 	}
 
-	response, err := h.repo.ToResponse(job)
+	response, err := repository.ToResponse(job)
 	if err != nil {
 		log.Printf("Failed to convert to response: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get training job"})
@@ -187,13 +474,65 @@ func (h *Handler) DeleteTrainingJob(c *gin.Context) {
 		return
 	}
 
+	karmadaClient, apiErr := h.jobKarmada(job)
+	if apiErr != nil {
+		apiErr.respond(c)
+		return
+	}
+
+	resources, err := h.jobResources(job)
+	if err != nil {
+		log.Printf("Failed to get resources of job %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job resources"})
+		return
+	}
+
+	// The leader finishes deleting whatever is left from the recorded resources
+	if job.Resources == "" {
+		if err := h.repo.SetJobResources(id, resources); err != nil {
+			log.Printf("Failed to record resources of job %s: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete training job"})
+			return
+		}
+	}
+
+	// The job stays Deleting, and out of the monitor, until everything is gone
+	if err := h.repo.UpdateTrainingJobStatus(id, "Deleting", "Deleting job resources"); err != nil {
+		log.Printf("Failed to mark job %s as deleting: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete training job"})
+		return
+	}
+
 	// Delete from Karmada
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), jobDeletionTimeout)
 	defer cancel()
 
-	if err := h.karmada.DeleteJob(ctx, job.JobName, job.Namespace); err != nil {
-		log.Printf("Failed to delete job from Karmada: %v", err)
-		// Continue with database deletion even if Karmada deletion fails
+	results := karmadaClient.DeleteJobResources(ctx, resources)
+	failed, pending := 0, 0
+	for _, result := range results {
+		switch result.Result {
+		case models.DeletionFailed:
+			failed++
+		case models.DeletionPending:
+			pending++
+		}
+	}
+
+	if failed > 0 || pending > 0 {
+		message := fmt.Sprintf("Cleanup incomplete: %d of %d resources failed to delete, %d still being removed; deletion continues in the background", failed, len(results), pending)
+		log.Printf("Job %s: %s", id, message)
+		if err := h.repo.UpdateTrainingJobStatus(id, "Deleting", message); err != nil {
+			log.Printf("Failed to update job status: %v", err)
+		}
+
+		// The job monitor of the leader retries the delete until every
+		// resource is gone and then removes the job
+		status := http.StatusAccepted
+		if failed > 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"status": "Deleting", "message": message, "resources": results})
+		return
 	}
 
 	// Delete from database
@@ -203,7 +542,25 @@ func (h *Handler) DeleteTrainingJob(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Training job deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Training job deleted successfully", "resources": results})
+}
+
+// jobResources returns the objects created for a job; for jobs created before
+// they were recorded, the objects the job would have
+func (h *Handler) jobResources(job *config.TrainingJob) ([]models.ResourceRef, error) {
+	if job.Resources != "" {
+		return repository.JobResources(job)
+	}
+
+	var req models.TrainingJobRequest
+	if err := json.Unmarshal([]byte(job.RequestPayload), &req); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal request payload: %w", err)
+	}
+	pvcName := ""
+	if jobConverter := h.converter(); jobConverter.OwnsPVC(&req) {
+		pvcName = jobConverter.ClaimName(&req)
+	}
+	return karmada.ExpectedJobResources(job.JobName, job.Namespace, pvcName), nil
 }
 
 // GetTrainingJobStatus handles GET /api/v1/jobs/:id/status
@@ -218,54 +575,139 @@ func (h *Handler) GetTrainingJobStatus(c *gin.Context) {
 		return
 	}
 
-	// Get live status from Karmada
+	response, err := repository.ToResponse(job)
+	if err != nil {
+		log.Printf("Failed to convert to response: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get training job status"})
+		return
+	}
+
+	karmadaClient, apiErr := h.jobKarmada(job)
+	if apiErr != nil {
+		apiErr.respond(c)
+		return
+	}
+
+	// The phase is kept current by the job monitor
+	status := models.JobStatus{
+		Phase:     job.Status,
+		Message:   job.Message,
+		Admission: job.Admission,
+	}
+
+	// Read the placement live from the RayJob's ResourceBinding. Only the
+	// job monitor of the leader records it, so reads stay read-only.
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	k8sJob, err := h.karmada.GetJobStatus(ctx, job.JobName, job.Namespace)
+	placement, err := karmadaClient.GetRayJobPlacement(ctx, job.JobName, job.Namespace)
 	if err != nil {
-		log.Printf("Failed to get job status from Karmada: %v", err)
-		// Return database status if Karmada query fails
-		c.JSON(http.StatusOK, gin.H{
-			"status":  job.Status,
-			"message": job.Message,
-		})
+		log.Printf("Failed to get placement from Karmada: %v", err)
+		// Fall back to the last recorded placement
+		placement = response.Placement
+	}
+
+	status.ClusterDistribution = clusterDistribution(placement, response.Request)
+
+	// Break the status down per cluster the RayJob runs in
+	clusters, err := jobstatus.CollectClusterStatuses(ctx, karmadaClient, job.JobName, job.Namespace)
+	if err != nil {
+		log.Printf("Failed to get RayJob status from member clusters: %v", err)
+		c.JSON(http.StatusOK, status)
 		return
 	}
+	status.Clusters = clusters
+	if phase, message, admission := jobstatus.AggregateClusterStatuses(clusters); phase != "" {
+		status.Phase = phase
+		status.Message = message
+		status.Admission = admission
+	}
+	status.StartTime, status.CompletionTime = clusterTimes(clusters, status.Phase)
 
-	// Build status response
-	status := models.JobStatus{
-		Active:    k8sJob.Status.Active,
-		Succeeded: k8sJob.Status.Succeeded,
-		Failed:    k8sJob.Status.Failed,
+	c.JSON(http.StatusOK, status)
+}
+
+// clusterTimes returns when the first cluster started the job and, once the
+// job is finished, when the last cluster completed it
+func clusterTimes(clusters []models.ClusterJobStatus, phase string) (start, completion time.Time) {
+	for _, cluster := range clusters {
+		if t, err := time.Parse(time.RFC3339, cluster.StartTime); err == nil && (start.IsZero() || t.Before(start)) {
+			start = t
+		}
+		if t, err := time.Parse(time.RFC3339, cluster.EndTime); err == nil && t.After(completion) {
+			completion = t
+		}
+	}
+	if phase != "Succeeded" && phase != "Failed" {
+		completion = time.Time{}
+	}
+	return start, completion
+}
+
+// clusterDistribution counts the pods of a job per member cluster. A RayJob
+// Karmada does not divide runs whole in every cluster it is placed on, so
+// clusters without assigned replicas run the head and all workers.
+func clusterDistribution(placement []models.ClusterPlacement, req *models.TrainingJobRequest) map[string]int32 {
+	if len(placement) == 0 {
+		return nil
 	}
 
-	if k8sJob.Status.StartTime != nil {
-		status.StartTime = k8sJob.Status.StartTime.Time
+	podsPerCluster := int32(1)
+	if req != nil {
+		podsPerCluster += int32(req.Resources.InstanceCount)
 	}
-	if k8sJob.Status.CompletionTime != nil {
-		status.CompletionTime = k8sJob.Status.CompletionTime.Time
+
+	distribution := make(map[string]int32, len(placement))
+	for _, p := range placement {
+		if p.Replicas > 0 {
+			distribution[p.Cluster] = p.Replicas
+		} else {
+			distribution[p.Cluster] = podsPerCluster
+		}
+	}
+	return distribution
+}
+
+// GetTrainingJobEvents handles GET /api/v1/jobs/:id/events
+// It merges the Kubernetes Events of the job's objects in Karmada and in the
+// member clusters it is placed on into one timeline, oldest first. "type"
+// keeps only Normal or Warning events.
+func (h *Handler) GetTrainingJobEvents(c *gin.Context) {
+	id := c.Param("id")
+
+	job, err := h.repo.GetTrainingJob(id)
+	if err != nil {
+		log.Printf("Failed to get training job: %v", err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "Training job not found"})
+		return
 	}
 
-	// Determine phase
-	if status.Succeeded > 0 {
-		status.Phase = "Succeeded"
-		status.Message = "Job completed successfully"
-	} else if status.Failed > 0 {
-		status.Phase = "Failed"
-		status.Message = "Job failed"
-	} else if status.Active > 0 {
-		status.Phase = "Running"
-		status.Message = "Job is running"
-	} else {
-		status.Phase = "Pending"
-		status.Message = "Job is pending"
+	karmadaClient, apiErr := h.jobKarmada(job)
+	if apiErr != nil {
+		apiErr.respond(c)
+		return
 	}
 
-	// Update database with latest status
-	h.repo.UpdateTrainingJobStatus(id, status.Phase, status.Message)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
 
-	c.JSON(http.StatusOK, status)
+	events, errs := karmadaClient.GetJobEvents(ctx, job.JobName, job.Namespace)
+	response := models.JobEventsResponse{JobID: id, Events: []models.JobEvent{}}
+	eventType := c.Query("type")
+	for _, event := range events {
+		if eventType == "" || strings.EqualFold(event.Type, eventType) {
+			response.Events = append(response.Events, event)
+		}
+	}
+	if len(errs) > 0 {
+		response.Errors = make(map[string]string, len(errs))
+		for cluster, err := range errs {
+			log.Printf("Failed to get events of job %s from %s: %v", id, cluster, err)
+			response.Errors[cluster] = err.Error()
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // GetTrainingJobLogs handles GET /api/v1/jobs/:id/logs
@@ -289,41 +731,206 @@ func (h *Handler) GetTrainingJobLogs(c *gin.Context) {
 }
 
 // ListMemberClusters handles GET /api/v1/proxy/clusters
+// The clusters are grouped by federation, or only those of the "federation"
+// parameter are listed. "clusters" has them all in one list.
 func (h *Handler) ListMemberClusters(c *gin.Context) {
+	names := h.federations.Names()
+	if federation := c.Query("federation"); federation != "" {
+		if _, apiErr := h.queryKarmada(c); apiErr != nil {
+			apiErr.respond(c)
+			return
+		}
+		names = []string{federation}
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	clusters, err := h.karmada.ListMemberClusters(ctx)
+	// A federation that cannot be reached does not hold up the others
+	groups := make([]models.FederationClusters, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			groups[i] = models.FederationClusters{Federation: name, Default: name == h.federations.Default(), Clusters: []models.ClusterInfo{}}
+			karmadaClient, err := h.federations.Get(name)
+			if err == nil {
+				var clusters []models.ClusterInfo
+				if clusters, err = karmadaClient.ListMemberClusterCapacity(ctx); err == nil {
+					groups[i].Clusters = clusters
+					return
+				}
+			}
+			log.Printf("Failed to list member clusters of federation %s: %v", name, err)
+			groups[i].Error = err.Error()
+		}(i, name)
+	}
+	wg.Wait()
+
+	failed := 0
+	clusters := []models.ClusterInfo{}
+	for _, group := range groups {
+		if group.Error != "" {
+			failed++
+		}
+		clusters = append(clusters, group.Clusters...)
+	}
+	if failed == len(groups) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list member clusters", "federations": groups})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"federations": groups, "clusters": clusters})
+}
+
+// GetClusterCapacity handles GET /api/v1/proxy/clusters/:cluster/capacity
+func (h *Handler) GetClusterCapacity(c *gin.Context) {
+	clusterName := c.Param("cluster")
+	karmadaClient, apiErr := h.queryKarmada(c)
+	if apiErr != nil {
+		apiErr.respond(c)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cluster, err := karmadaClient.GetClusterCapacity(ctx, clusterName)
 	if err != nil {
-		log.Printf("Failed to list member clusters: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list member clusters"})
+		log.Printf("Failed to get cluster capacity: %v", err)
+		if apierrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Member cluster %s not found", clusterName)})
+			return
+		}
+		c.JSON(statusForError(err), gin.H{"error": fmt.Sprintf("Failed to get cluster capacity: %v", err)})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"clusters": clusters})
+	c.JSON(http.StatusOK, cluster)
 }
 
 // GetClusterResources handles GET /api/v1/proxy/clusters/:cluster/resources
 func (h *Handler) GetClusterResources(c *gin.Context) {
 	clusterName := c.Param("cluster")
-	namespace := c.DefaultQuery("namespace", "default")
-	resourceType := c.DefaultQuery("type", "pods")
+	query, err := resourceQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	karmadaClient, apiErr := h.queryKarmada(c)
+	if apiErr != nil {
+		apiErr.respond(c)
+		return
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	resources, err := h.karmada.GetClusterResources(ctx, clusterName, namespace, resourceType)
+	resources, err := karmadaClient.ListClusterResources(ctx, clusterName, query)
 	if err != nil {
 		log.Printf("Failed to get cluster resources: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get resources: %v", err)})
+		c.JSON(statusForError(err), gin.H{"error": fmt.Sprintf("Failed to get resources: %v", err)})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"cluster":   clusterName,
-		"namespace": namespace,
-		"type":      resourceType,
-		"count":     len(resources),
-		"resources": resources,
+		"namespace": query.Namespace,
+		"type":      query.Resource,
+		"kind":      resources.Kind,
+		"count":     resources.Count,
+		"continue":  resources.Continue,
+		"resources": resources.Resources,
 	})
 }
+
+// ListResources handles GET /api/v1/proxy/resources, listing from several
+// member clusters of a federation at once: those in the comma-separated
+// "clusters" parameter, or all of them
+func (h *Handler) ListResources(c *gin.Context) {
+	query, err := resourceQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	karmadaClient, apiErr := h.queryKarmada(c)
+	if apiErr != nil {
+		apiErr.respond(c)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var clusters []string
+	for _, name := range strings.Split(c.Query("clusters"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			clusters = append(clusters, name)
+		}
+	}
+	if len(clusters) == 0 {
+		members, err := karmadaClient.ListMemberClusters(ctx)
+		if err != nil {
+			log.Printf("Failed to list member clusters: %v", err)
+			c.JSON(statusForError(err), gin.H{"error": "Failed to list member clusters"})
+			return
+		}
+		for _, member := range members {
+			clusters = append(clusters, member.Name)
+		}
+	}
+
+	results := karmadaClient.ListResourcesInClusters(ctx, clusters, query)
+	count := 0
+	for _, result := range results {
+		count += result.Count
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"namespace": query.Namespace,
+		"type":      query.Resource,
+		"count":     count,
+		"clusters":  results,
+	})
+}
+
+// resourceQuery reads the resource type, namespace, selectors and paging of
+// the resource endpoints. "jobId" selects the objects labeled with a job's ID.
+func resourceQuery(c *gin.Context) (karmada.ResourceQuery, error) {
+	query := karmada.ResourceQuery{
+		Group:         c.Query("group"),
+		Version:       c.Query("version"),
+		Resource:      c.Query("resource"),
+		Namespace:     c.DefaultQuery("namespace", "default"),
+		LabelSelector: c.Query("labelSelector"),
+		FieldSelector: c.Query("fieldSelector"),
+		Continue:      c.Query("continue"),
+	}
+	if query.Resource == "" {
+		query.Resource = c.DefaultQuery("type", "pods")
+	}
+	if c.Query("allNamespaces") == "true" {
+		query.Namespace = ""
+	}
+
+	if jobID := c.Query("jobId"); jobID != "" {
+		if errs := validation.IsValidLabelValue(jobID); len(errs) > 0 {
+			return query, fmt.Errorf("invalid jobId: %s", strings.Join(errs, "; "))
+		}
+		selector := "training-job-id=" + jobID
+		if query.LabelSelector != "" {
+			selector = query.LabelSelector + "," + selector
+		}
+		query.LabelSelector = selector
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.ParseInt(limit, 10, 64)
+		if err != nil || n <= 0 {
+			return query, fmt.Errorf("limit must be a positive number")
+		}
+		query.Limit = n
+	}
+	return query, nil
+}
@@ -0,0 +1,718 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/loiht2/ml-platform-training-job/backend/config"
+	"github.com/loiht2/ml-platform-training-job/backend/converter"
+	"github.com/loiht2/ml-platform-training-job/backend/karmada"
+	"github.com/loiht2/ml-platform-training-job/backend/karmada/fake"
+	"github.com/loiht2/ml-platform-training-job/backend/leader"
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+	"github.com/loiht2/ml-platform-training-job/backend/monitor"
+	"github.com/loiht2/ml-platform-training-job/backend/repository"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// testServer is the API backed by an in-memory store and two fake Karmada
+// federations: the default one and "research", which serves the research namespace
+type testServer struct {
+	router   *gin.Engine
+	store    *repository.MemoryStore
+	karmada  *fake.Client
+	research *fake.Client
+}
+
+// newTestServer builds the test API; configure, when given, adjusts the
+// platform settings before the handler is created
+func newTestServer(t *testing.T, configure ...func(*config.Settings)) *testServer {
+	t.Helper()
+	s := &testServer{
+		store: repository.NewMemoryStore(),
+		karmada: fake.NewClient(
+			testCluster("member-1", 4),
+			testCluster("member-2", 2),
+		),
+		research: fake.NewClient(testCluster("lab-1", 8)),
+		router:   gin.New(),
+	}
+	settings := &config.Settings{
+		Federations: []config.FederationSettings{
+			{Name: "research", Kubeconfig: "/etc/karmada/research.kubeconfig", Namespaces: []string{"research"}},
+		},
+		Defaults: &config.JobDefaults{
+			Storage: config.StorageCredentials{AccessKey: "platform", SecretKey: "platform-secret"},
+		},
+	}
+	for _, fn := range configure {
+		fn(settings)
+	}
+	federations := karmada.NewFederations(settings.DefaultFederationName(), map[string]karmada.Interface{
+		settings.DefaultFederationName(): s.karmada,
+		"research":                       s.research,
+	})
+	elector, err := leader.New(nil, "", "replica-1")
+	if err != nil {
+		t.Fatalf("failed to create elector: %v", err)
+	}
+	jobMonitor := monitor.NewJobMonitor(s.store, federations, config.MonitorSettings{})
+	handler := NewHandler(&config.Config{Settings: settings}, s.store, federations, elector, func() (models.MonitorStats, bool) {
+		return jobMonitor.Stats(), true
+	})
+	handler.RegisterRoutes(s.router.Group("/api/v1"))
+	return s
+}
+
+func testCluster(name string, gpus int64) models.ClusterInfo {
+	free := models.ResourceAmounts{CPUCores: 64, MemoryGiB: 256, GPUs: gpus, Pods: 110}
+	return models.ClusterInfo{
+		Name:     name,
+		Ready:    true,
+		Capacity: &models.ClusterCapacity{Allocatable: free, Available: free},
+	}
+}
+
+func testRequest() models.TrainingJobRequest {
+	return models.TrainingJobRequest{
+		JobName:   "xgboost-training",
+		Namespace: "default",
+		Algorithm: models.Algorithm{Source: "builtin", AlgorithmName: "xgboost"},
+		Resources: models.Resources{
+			InstanceResources: models.InstanceResources{CPUCores: 4, MemoryGiB: 8},
+			InstanceCount:     1,
+			VolumeSizeGB:      20,
+		},
+	}
+}
+
+// do sends a request and decodes the JSON response into out, when set
+func (s *testServer) do(t *testing.T, method, path string, body interface{}, out interface{}) int {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("failed to marshal request body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	s.router.ServeHTTP(recorder, req)
+
+	if out != nil {
+		if err := json.Unmarshal(recorder.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: failed to decode response %q: %v", method, path, recorder.Body.String(), err)
+		}
+	}
+	return recorder.Code
+}
+
+// createJob submits a job and returns its ID
+func (s *testServer) createJob(t *testing.T, req models.TrainingJobRequest) string {
+	t.Helper()
+	var job models.TrainingJobResponse
+	if code := s.do(t, http.MethodPost, "/api/v1/jobs", req, &job); code != http.StatusCreated {
+		t.Fatalf("create job: got status %d, want %d", code, http.StatusCreated)
+	}
+	return job.ID
+}
+
+func TestCreateTrainingJob(t *testing.T) {
+	s := newTestServer(t)
+
+	var job models.TrainingJobResponse
+	code := s.do(t, http.MethodPost, "/api/v1/jobs", testRequest(), &job)
+	if code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d", code, http.StatusCreated)
+	}
+	if !strings.HasPrefix(job.ID, "xgboost-training-") {
+		t.Errorf("got job ID %q, want it to start with the job name", job.ID)
+	}
+	if job.Status != "Pending" {
+		t.Errorf("got status %q, want Pending until the job monitor sees it run", job.Status)
+	}
+
+	kinds := map[string]bool{}
+	for _, ref := range job.Resources {
+		kinds[ref.Kind] = true
+	}
+	for _, kind := range []string{"PersistentVolumeClaim", "RayJob", "PropagationPolicy"} {
+		if !kinds[kind] {
+			t.Errorf("resources %v are missing a %s", job.Resources, kind)
+		}
+	}
+
+	// The cluster with the most free GPUs is preferred
+	if s.karmada.RayJob("member-1", "default", "xgboost-training") == nil {
+		t.Errorf("RayJob was not scheduled to member-1")
+	}
+	opts, _ := s.karmada.Propagation("default", "xgboost-training")
+	if opts.PreferredCluster != "member-1" {
+		t.Errorf("got preferred cluster %q, want member-1", opts.PreferredCluster)
+	}
+
+	stored, err := s.store.GetTrainingJob(job.ID)
+	if err != nil {
+		t.Fatalf("job was not stored: %v", err)
+	}
+	if stored.Status != "Pending" {
+		t.Errorf("got stored status %q, want Pending", stored.Status)
+	}
+}
+
+func TestCreateCustomTrainingJobPropagatesInlineScript(t *testing.T) {
+	s := newTestServer(t)
+	req := testRequest()
+	req.Algorithm = models.Algorithm{
+		Source:     "custom",
+		Entrypoint: "python main.py",
+		Code:       &models.CustomCode{Inline: &models.InlineCodeSource{Script: "print('training')"}},
+	}
+
+	var job models.TrainingJobResponse
+	if code := s.do(t, http.MethodPost, "/api/v1/jobs", req, &job); code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d", code, http.StatusCreated)
+	}
+
+	configMap := models.ResourceRef{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "xgboost-training-code"}
+	recorded := false
+	for _, ref := range job.Resources {
+		recorded = recorded || ref == configMap
+	}
+	if !recorded {
+		t.Errorf("resources %v are missing the code ConfigMap", job.Resources)
+	}
+
+	opts, _ := s.karmada.Propagation("default", "xgboost-training")
+	propagated := false
+	for _, dependency := range opts.Dependencies {
+		propagated = propagated || (dependency.Kind == "ConfigMap" && dependency.Name == configMap.Name)
+	}
+	if !propagated {
+		t.Errorf("dependencies %v are missing the code ConfigMap", opts.Dependencies)
+	}
+}
+
+func TestCreateTrainingJobPropagatesStorageSecret(t *testing.T) {
+	s := newTestServer(t)
+	req := testRequest()
+	req.OutputDataConfig = models.OutputDataConfig{ArtifactURI: "s3://models/churn", Endpoint: "minio.local:9000"}
+
+	var job models.TrainingJobResponse
+	if code := s.do(t, http.MethodPost, "/api/v1/jobs", req, &job); code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d", code, http.StatusCreated)
+	}
+
+	secret := models.ResourceRef{APIVersion: "v1", Kind: "Secret", Namespace: "default", Name: "xgboost-training-storage"}
+	recorded := false
+	for _, ref := range job.Resources {
+		recorded = recorded || ref == secret
+	}
+	if !recorded {
+		t.Errorf("resources %v are missing the storage Secret", job.Resources)
+	}
+
+	opts, _ := s.karmada.Propagation("default", "xgboost-training")
+	propagated := false
+	for _, dependency := range opts.Dependencies {
+		propagated = propagated || (dependency.Kind == "Secret" && dependency.Name == secret.Name)
+	}
+	if !propagated {
+		t.Errorf("dependencies %v are missing the storage Secret", opts.Dependencies)
+	}
+}
+
+func TestCreateTrainingJobFallsBackToReadyFittingClusters(t *testing.T) {
+	tests := []struct {
+		name   string
+		gpus   int
+		modify func(s *testServer)
+		want   []string
+	}{
+		{"every cluster fits", 0, func(*testServer) {}, []string{"member-2"}},
+		{"cluster too small", 3, func(*testServer) {}, nil},
+		{"cluster not ready", 0, func(s *testServer) { s.karmada.SetClusterReady("member-2", false) }, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			tt.modify(s)
+			req := testRequest()
+			req.Resources.InstanceResources.GPUCount = tt.gpus
+			s.createJob(t, req)
+
+			opts, _ := s.karmada.Propagation("default", "xgboost-training")
+			if opts.PreferredCluster != "member-1" {
+				t.Errorf("got preferred cluster %q, want member-1", opts.PreferredCluster)
+			}
+			if !reflect.DeepEqual(opts.FallbackClusters, tt.want) {
+				t.Errorf("got fallback clusters %v, want %v", opts.FallbackClusters, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateTrainingJobQueuesForPreferredCluster(t *testing.T) {
+	s := newTestServer(t, func(settings *config.Settings) {
+		settings.Clusters = map[string]config.ClusterSettings{
+			"member-1": {Queueing: &config.QueueingSettings{Type: config.QueueingVolcano, VolcanoQueue: "training"}},
+		}
+	})
+	s.createJob(t, testRequest())
+
+	opts, _ := s.karmada.Propagation("default", "xgboost-training")
+	if opts.PreferredCluster != "member-1" {
+		t.Errorf("got preferred cluster %q, want member-1", opts.PreferredCluster)
+	}
+	if len(opts.FallbackClusters) != 0 {
+		t.Errorf("got fallback clusters %v, want none since member-2 is not gang scheduled", opts.FallbackClusters)
+	}
+
+	rayJob := &unstructured.Unstructured{Object: s.karmada.RayJob("member-1", "default", "xgboost-training")}
+	if queue := rayJob.GetLabels()[converter.VolcanoQueueNameLabel]; queue != "training" {
+		t.Errorf("got Volcano queue %q, want training", queue)
+	}
+}
+
+func TestCreateTrainingJobRejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(req *models.TrainingJobRequest)
+		want   int
+	}{
+		{"missing job name", func(req *models.TrainingJobRequest) { req.JobName = "" }, http.StatusBadRequest},
+		{"unsupported algorithm", func(req *models.TrainingJobRequest) { req.Algorithm.AlgorithmName = "tensorflow" }, http.StatusBadRequest},
+		{"unknown algorithm source", func(req *models.TrainingJobRequest) { req.Algorithm.Source = "marketplace" }, http.StatusBadRequest},
+		{"unknown target cluster", func(req *models.TrainingJobRequest) { req.TargetClusters = []string{"member-9"} }, http.StatusBadRequest},
+		{"no cluster fits", func(req *models.TrainingJobRequest) { req.Resources.InstanceResources.GPUCount = 8 }, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			req := testRequest()
+			tt.modify(&req)
+
+			var body map[string]interface{}
+			if code := s.do(t, http.MethodPost, "/api/v1/jobs", req, &body); code != tt.want {
+				t.Fatalf("got status %d (%v), want %d", code, body, tt.want)
+			}
+			if jobs, _ := s.store.ListTrainingJobs(""); len(jobs) != 0 {
+				t.Errorf("rejected job was stored: %v", jobs)
+			}
+			if objects := s.karmada.Objects(); len(objects) != 0 {
+				t.Errorf("rejected job created %v", objects)
+			}
+		})
+	}
+}
+
+func TestCreateTrainingJobKarmadaFailure(t *testing.T) {
+	s := newTestServer(t)
+	s.karmada.FailOn("CreateRayJobWithPropagationPolicy", errors.New("admission webhook denied the request"))
+
+	var body map[string]interface{}
+	if code := s.do(t, http.MethodPost, "/api/v1/jobs", testRequest(), &body); code != http.StatusInternalServerError {
+		t.Fatalf("got status %d, want %d", code, http.StatusInternalServerError)
+	}
+
+	jobs, _ := s.store.ListTrainingJobs("")
+	if len(jobs) != 1 {
+		t.Fatalf("got %d stored jobs, want 1", len(jobs))
+	}
+	if jobs[0].Status != "Failed" || !strings.Contains(jobs[0].Message, "admission webhook denied") {
+		t.Errorf("got status %q (%s), want Failed with the Karmada error", jobs[0].Status, jobs[0].Message)
+	}
+
+	// The PVC created before the failure is recorded, so deleting the job removes it
+	resources, err := repository.JobResources(&jobs[0])
+	if err != nil {
+		t.Fatalf("failed to read job resources: %v", err)
+	}
+	if len(resources) != 1 || resources[0].Kind != "PersistentVolumeClaim" {
+		t.Errorf("got resources %v, want the PVC", resources)
+	}
+}
+
+func TestListAndGetTrainingJobs(t *testing.T) {
+	s := newTestServer(t)
+	first := s.createJob(t, testRequest())
+	req := testRequest()
+	req.JobName = "other-training"
+	req.Namespace = "team-a"
+	second := s.createJob(t, req)
+
+	var all []models.TrainingJobResponse
+	if code := s.do(t, http.MethodGet, "/api/v1/jobs", nil, &all); code != http.StatusOK {
+		t.Fatalf("list: got status %d, want %d", code, http.StatusOK)
+	}
+	if len(all) != 2 {
+		t.Errorf("list: got %d jobs, want 2", len(all))
+	}
+
+	var filtered []models.TrainingJobResponse
+	s.do(t, http.MethodGet, "/api/v1/jobs?namespace=team-a", nil, &filtered)
+	if len(filtered) != 1 || filtered[0].ID != second {
+		t.Errorf("list team-a: got %v, want only %s", filtered, second)
+	}
+
+	var job models.TrainingJobResponse
+	if code := s.do(t, http.MethodGet, "/api/v1/jobs/"+first, nil, &job); code != http.StatusOK {
+		t.Fatalf("get: got status %d, want %d", code, http.StatusOK)
+	}
+	if job.ID != first || job.Request == nil || job.Request.JobName != "xgboost-training" {
+		t.Errorf("get: got %+v, want job %s with its request", job, first)
+	}
+
+	if code := s.do(t, http.MethodGet, "/api/v1/jobs/missing", nil, nil); code != http.StatusNotFound {
+		t.Errorf("get missing job: got status %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestGetTrainingJobStatus(t *testing.T) {
+	s := newTestServer(t)
+	id := s.createJob(t, testRequest())
+	before, err := s.store.GetTrainingJob(id)
+	if err != nil {
+		t.Fatalf("failed to get stored job: %v", err)
+	}
+
+	var status models.JobStatus
+	if code := s.do(t, http.MethodGet, "/api/v1/jobs/"+id+"/status", nil, &status); code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", code, http.StatusOK)
+	}
+	if status.Phase != "Pending" {
+		t.Errorf("before the RayJob started: got phase %q, want Pending", status.Phase)
+	}
+
+	s.karmada.Step("default", "xgboost-training")
+	s.karmada.Step("default", "xgboost-training")
+	s.do(t, http.MethodGet, "/api/v1/jobs/"+id+"/status", nil, &status)
+	if status.Phase != "Running" {
+		t.Errorf("got phase %q, want Running", status.Phase)
+	}
+	if len(status.Clusters) != 1 || status.Clusters[0].Cluster != "member-1" {
+		t.Errorf("got clusters %+v, want member-1", status.Clusters)
+	}
+	if status.ClusterDistribution["member-1"] != 2 {
+		t.Errorf("got distribution %v, want the head and one worker in member-1", status.ClusterDistribution)
+	}
+
+	s.karmada.SetRayJobStatus("member-1", "default", "xgboost-training", fake.DeploymentFailed, fake.JobFailed)
+	s.do(t, http.MethodGet, "/api/v1/jobs/"+id+"/status", nil, &status)
+	if status.Phase != "Failed" || status.CompletionTime.IsZero() {
+		t.Errorf("got phase %q completed at %v, want Failed with a completion time", status.Phase, status.CompletionTime)
+	}
+
+	// Reads leave recording the status and placement to the job monitor
+	stored, err := s.store.GetTrainingJob(id)
+	if err != nil {
+		t.Fatalf("failed to get stored job: %v", err)
+	}
+	if stored.Status != before.Status || stored.Placement != before.Placement {
+		t.Errorf("stored job changed by status reads: status %q, placement %s", stored.Status, stored.Placement)
+	}
+
+	if code := s.do(t, http.MethodGet, "/api/v1/jobs/missing/status", nil, nil); code != http.StatusNotFound {
+		t.Errorf("status of missing job: got status %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestDeleteTrainingJob(t *testing.T) {
+	s := newTestServer(t)
+	id := s.createJob(t, testRequest())
+
+	var body struct {
+		Resources []models.DeletionResult `json:"resources"`
+	}
+	if code := s.do(t, http.MethodDelete, "/api/v1/jobs/"+id, nil, &body); code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", code, http.StatusOK)
+	}
+	for _, result := range body.Resources {
+		if result.Result != models.DeletionDeleted {
+			t.Errorf("%s %s: got result %q, want %q", result.Kind, result.Name, result.Result, models.DeletionDeleted)
+		}
+	}
+
+	if objects := s.karmada.Objects(); len(objects) != 0 {
+		t.Errorf("objects left in Karmada: %v", objects)
+	}
+	if s.karmada.RayJob("member-1", "default", "xgboost-training") != nil {
+		t.Errorf("RayJob left in member-1")
+	}
+	if _, err := s.store.GetTrainingJob(id); err == nil {
+		t.Errorf("job is still stored")
+	}
+
+	if code := s.do(t, http.MethodDelete, "/api/v1/jobs/"+id, nil, nil); code != http.StatusNotFound {
+		t.Errorf("delete again: got status %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestDeleteTrainingJobFailureKeepsJob(t *testing.T) {
+	s := newTestServer(t)
+	id := s.createJob(t, testRequest())
+	s.karmada.FailOn("DeleteJobResources", errors.New("connection refused"))
+
+	var body map[string]interface{}
+	if code := s.do(t, http.MethodDelete, "/api/v1/jobs/"+id, nil, &body); code != http.StatusInternalServerError {
+		t.Fatalf("got status %d, want %d", code, http.StatusInternalServerError)
+	}
+	if body["status"] != "Deleting" {
+		t.Errorf("got response %v, want status Deleting", body)
+	}
+
+	job, err := s.store.GetTrainingJob(id)
+	if err != nil {
+		t.Fatalf("job was removed although its resources were not: %v", err)
+	}
+	if job.Status != "Deleting" || !strings.Contains(job.Message, "3 of 3 resources failed to delete") {
+		t.Errorf("got status %q (%s), want Deleting with the failed deletes", job.Status, job.Message)
+	}
+
+	// Retrying once Karmada is reachable again finishes the cleanup
+	s.karmada.FailOn("DeleteJobResources", nil)
+	if code := s.do(t, http.MethodDelete, "/api/v1/jobs/"+id, nil, nil); code != http.StatusOK {
+		t.Fatalf("retry: got status %d, want %d", code, http.StatusOK)
+	}
+	if _, err := s.store.GetTrainingJob(id); err == nil {
+		t.Errorf("job is still stored after the retry")
+	}
+}
+
+func TestFederationRouting(t *testing.T) {
+	s := newTestServer(t)
+
+	named := testRequest()
+	named.Federation = "research"
+	namedID := s.createJob(t, named)
+
+	mapped := testRequest()
+	mapped.JobName = "mapped-training"
+	mapped.Namespace = "research"
+	mappedID := s.createJob(t, mapped)
+
+	defaulted := testRequest()
+	defaulted.JobName = "default-training"
+	defaultedID := s.createJob(t, defaulted)
+
+	if s.research.RayJob("lab-1", "default", "xgboost-training") == nil {
+		t.Errorf("job naming the research federation was not created there")
+	}
+	if s.research.RayJob("lab-1", "research", "mapped-training") == nil {
+		t.Errorf("job in the research namespace was not created in the research federation")
+	}
+	if s.karmada.RayJob("member-1", "default", "default-training") == nil {
+		t.Errorf("job without a federation was not created in the default federation")
+	}
+	for id, want := range map[string]string{namedID: "research", mappedID: "research", defaultedID: "default"} {
+		var job models.TrainingJobResponse
+		s.do(t, http.MethodGet, "/api/v1/jobs/"+id, nil, &job)
+		if job.Federation != want {
+			t.Errorf("job %s: got federation %q, want %q", id, job.Federation, want)
+		}
+	}
+
+	var status models.JobStatus
+	s.do(t, http.MethodGet, "/api/v1/jobs/"+namedID+"/status", nil, &status)
+	if len(status.Clusters) != 1 || status.Clusters[0].Cluster != "lab-1" {
+		t.Errorf("got clusters %+v, want lab-1 of the research federation", status.Clusters)
+	}
+
+	if code := s.do(t, http.MethodDelete, "/api/v1/jobs/"+namedID, nil, nil); code != http.StatusOK {
+		t.Fatalf("delete: got status %d, want %d", code, http.StatusOK)
+	}
+	if s.research.RayJob("lab-1", "default", "xgboost-training") != nil {
+		t.Errorf("RayJob left in the research federation")
+	}
+
+	unknown := testRequest()
+	unknown.Federation = "staging"
+	if code := s.do(t, http.MethodPost, "/api/v1/jobs", unknown, nil); code != http.StatusBadRequest {
+		t.Errorf("unknown federation: got status %d, want %d", code, http.StatusBadRequest)
+	}
+}
+
+func TestListMemberClustersByFederation(t *testing.T) {
+	s := newTestServer(t)
+
+	var body struct {
+		Federations []models.FederationClusters `json:"federations"`
+		Clusters    []models.ClusterInfo        `json:"clusters"`
+	}
+	if code := s.do(t, http.MethodGet, "/api/v1/proxy/clusters", nil, &body); code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", code, http.StatusOK)
+	}
+	if len(body.Federations) != 2 || body.Federations[0].Federation != "default" || !body.Federations[0].Default ||
+		len(body.Federations[0].Clusters) != 2 || body.Federations[1].Federation != "research" || len(body.Federations[1].Clusters) != 1 {
+		t.Errorf("got federations %+v, want default with 2 clusters, then research with 1", body.Federations)
+	}
+	if len(body.Clusters) != 3 {
+		t.Errorf("got %d clusters in total, want 3", len(body.Clusters))
+	}
+
+	// An unreachable federation is reported without failing the others
+	s.research.FailOn("ListMemberClusterCapacity", errors.New("connection refused"))
+	s.do(t, http.MethodGet, "/api/v1/proxy/clusters", nil, &body)
+	if body.Federations[1].Error == "" || len(body.Federations[0].Clusters) != 2 {
+		t.Errorf("got federations %+v, want an error for research only", body.Federations)
+	}
+	if code := s.do(t, http.MethodGet, "/api/v1/proxy/clusters?federation=research", nil, nil); code != http.StatusInternalServerError {
+		t.Errorf("only the unreachable federation: got status %d, want %d", code, http.StatusInternalServerError)
+	}
+
+	var one struct {
+		Federations []models.FederationClusters `json:"federations"`
+	}
+	s.do(t, http.MethodGet, "/api/v1/proxy/clusters?federation=default", nil, &one)
+	if len(one.Federations) != 1 || one.Federations[0].Federation != "default" {
+		t.Errorf("got federations %+v, want only default", one.Federations)
+	}
+	if code := s.do(t, http.MethodGet, "/api/v1/proxy/clusters?federation=staging", nil, nil); code != http.StatusNotFound {
+		t.Errorf("unknown federation: got status %d, want %d", code, http.StatusNotFound)
+	}
+
+	var capacity models.ClusterInfo
+	if code := s.do(t, http.MethodGet, "/api/v1/proxy/clusters/lab-1/capacity?federation=research", nil, &capacity); code != http.StatusOK || capacity.Name != "lab-1" {
+		t.Errorf("capacity of lab-1 in research: got status %d and %+v", code, capacity)
+	}
+	if code := s.do(t, http.MethodGet, "/api/v1/proxy/clusters/lab-1/capacity", nil, nil); code != http.StatusNotFound {
+		t.Errorf("lab-1 in the default federation: got status %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestGetTrainingJobEvents(t *testing.T) {
+	s := newTestServer(t)
+	req := testRequest()
+	req.TargetClusters = []string{"member-1", "member-2"}
+	id := s.createJob(t, req)
+
+	start := time.Now()
+	s.karmada.AddEvent("default", "xgboost-training", models.JobEvent{
+		Time: start.Add(2 * time.Second), Cluster: "member-2", Kind: "Pod", Name: "xgboost-training-head",
+		Type: "Warning", Reason: "FailedScheduling", Message: "0/3 nodes are available: 3 Insufficient nvidia.com/gpu.",
+	})
+	s.karmada.AddEvent("default", "xgboost-training", models.JobEvent{
+		Time: start, Cluster: models.KarmadaEventSource, Kind: "ResourceBinding", Name: "xgboost-training-rayjob",
+		Type: "Normal", Reason: "ScheduleBindingSucceed", Message: "Binding has been scheduled successfully.",
+	})
+	s.karmada.AddEvent("default", "xgboost-training", models.JobEvent{
+		Time: start.Add(time.Second), Cluster: "member-1", Kind: "RayJob", Name: "xgboost-training",
+		Type: "Normal", Reason: "Created", Message: "Created RayCluster xgboost-training-raycluster",
+	})
+
+	var timeline models.JobEventsResponse
+	if code := s.do(t, http.MethodGet, "/api/v1/jobs/"+id+"/events", nil, &timeline); code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", code, http.StatusOK)
+	}
+	var sources []string
+	for _, event := range timeline.Events {
+		sources = append(sources, event.Cluster)
+	}
+	if strings.Join(sources, ",") != "karmada,member-1,member-2" {
+		t.Errorf("got events from %v, want karmada, member-1 and member-2 in time order", sources)
+	}
+
+	var warnings models.JobEventsResponse
+	s.do(t, http.MethodGet, "/api/v1/jobs/"+id+"/events?type=Warning", nil, &warnings)
+	if len(warnings.Events) != 1 || warnings.Events[0].Reason != "FailedScheduling" {
+		t.Errorf("got warnings %+v, want the FailedScheduling event", warnings.Events)
+	}
+
+	// A cluster that cannot be read is reported next to the events of the others
+	s.karmada.SetClusterReady("member-2", false)
+	s.do(t, http.MethodGet, "/api/v1/jobs/"+id+"/events", nil, &timeline)
+	if len(timeline.Events) != 2 || timeline.Errors["member-2"] == "" {
+		t.Errorf("got %d events and errors %v, want 2 events and an error for member-2", len(timeline.Events), timeline.Errors)
+	}
+
+	if code := s.do(t, http.MethodGet, "/api/v1/jobs/missing/events", nil, nil); code != http.StatusNotFound {
+		t.Errorf("events of missing job: got status %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestGetActiveConfig(t *testing.T) {
+	s := newTestServer(t)
+
+	var resp struct {
+		Version  config.SettingsVersion `json:"version"`
+		Settings config.Settings        `json:"settings"`
+	}
+	if code := s.do(t, http.MethodGet, "/api/v1/debug/config", nil, &resp); code != http.StatusOK {
+		t.Fatalf("get config: got status %d, want %d", code, http.StatusOK)
+	}
+	if len(resp.Settings.Federations) != 1 || resp.Settings.Federations[0].Name != "research" {
+		t.Errorf("federations = %+v, want research", resp.Settings.Federations)
+	}
+	if storage := resp.Settings.Defaults.Storage; storage.AccessKey != "REDACTED" || storage.SecretKey != "REDACTED" {
+		t.Errorf("storage credentials = %+v, want them redacted", storage)
+	}
+}
+
+func TestGetMonitorStats(t *testing.T) {
+	s := newTestServer(t)
+
+	var stats models.MonitorStats
+	if code := s.do(t, http.MethodGet, "/api/v1/debug/monitor", nil, &stats); code != http.StatusOK {
+		t.Fatalf("get monitor stats: got status %d, want %d", code, http.StatusOK)
+	}
+	if stats.Workers != 4 || stats.ResyncSeconds != 60 || stats.Backlog != 0 {
+		t.Errorf("stats = %+v, want 4 workers, 60s resync and no backlog", stats)
+	}
+	if len(stats.PolledFederations) != 2 {
+		t.Errorf("polled federations = %v, want both unwatched federations", stats.PolledFederations)
+	}
+}
+
+func TestGetLeaderStatus(t *testing.T) {
+	s := newTestServer(t)
+
+	var status leader.Status
+	if code := s.do(t, http.MethodGet, "/api/v1/debug/leader", nil, &status); code != http.StatusOK {
+		t.Fatalf("get leader: got status %d, want %d", code, http.StatusOK)
+	}
+	if status.Enabled || !status.IsLeader || status.Leader != "replica-1" {
+		t.Errorf("status = %+v, want replica-1 leading without election", status)
+	}
+}
+
+func TestGetMonitorStatsOnFollower(t *testing.T) {
+	elector, err := leader.New(nil, "", "replica-2")
+	if err != nil {
+		t.Fatalf("failed to create elector: %v", err)
+	}
+	router := gin.New()
+	NewHandler(&config.Config{}, repository.NewMemoryStore(), nil, elector, func() (models.MonitorStats, bool) {
+		return models.MonitorStats{}, false
+	}).RegisterRoutes(router.Group("/api/v1"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/debug/monitor", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes adds the API routes to the /api/v1 group
+func (h *Handler) RegisterRoutes(api *gin.RouterGroup) {
+	// Training job routes
+	jobs := api.Group("/jobs")
+	{
+		jobs.POST("", h.CreateTrainingJob)
+		jobs.POST("/import", h.ImportRayJob)
+		jobs.GET("", h.ListTrainingJobs)
+		jobs.GET("/:id", h.GetTrainingJob)
+		jobs.DELETE("/:id", h.DeleteTrainingJob)
+		jobs.GET("/:id/status", h.GetTrainingJobStatus)
+		jobs.GET("/:id/events", h.GetTrainingJobEvents)
+		jobs.GET("/:id/logs", h.GetTrainingJobLogs)
+	}
+
+	// Member cluster resources proxy
+	proxy := api.Group("/proxy")
+	{
+		proxy.GET("/clusters", h.ListMemberClusters)
+		proxy.GET("/clusters/:cluster/resources", h.GetClusterResources)
+		proxy.GET("/clusters/:cluster/capacity", h.GetClusterCapacity)
+		proxy.GET("/resources", h.ListResources)
+	}
+
+	// Diagnostics
+	api.GET("/debug/config", h.GetActiveConfig)
+	api.GET("/debug/monitor", h.GetMonitorStats)
+	api.GET("/debug/leader", h.GetLeaderStatus)
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/loiht2/ml-platform-training-job/backend/config"
+	"github.com/loiht2/ml-platform-training-job/backend/converter"
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// validateTrainingJobRequest checks the request fields the converter relies
+// on, and that the platform settings can serve what the request uses
+func validateTrainingJobRequest(req *models.TrainingJobRequest, settings *config.Settings) error {
+	switch req.Algorithm.Source {
+	case "", converter.AlgorithmSourceBuiltin:
+		if req.Algorithm.Code != nil {
+			return fmt.Errorf("algorithm.code is only supported for custom algorithms")
+		}
+	case converter.AlgorithmSourceCustom:
+		if err := converter.ValidateCustomAlgorithm(&req.Algorithm); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported algorithm source %q (expected \"builtin\" or \"custom\")", req.Algorithm.Source)
+	}
+
+	if err := converter.ValidateVolume(&req.Resources); err != nil {
+		return err
+	}
+
+	if err := converter.ValidateDataStaging(req); err != nil {
+		return err
+	}
+
+	if err := converter.ValidateOutputDataConfig(&req.OutputDataConfig, req.InputDataConfig); err != nil {
+		return err
+	}
+
+	if err := converter.ValidateStorageCredentials(req, settings.JobDefaults().Storage); err != nil {
+		return err
+	}
+
+	if err := req.Placement.Validate(); err != nil {
+		return fmt.Errorf("placement: %w", err)
+	}
+
+	for cluster, overrides := range req.ClusterOverrides {
+		if err := overrides.Validate(); err != nil {
+			return fmt.Errorf("clusterOverrides.%s: %w", cluster, err)
+		}
+	}
+
+	return nil
+}
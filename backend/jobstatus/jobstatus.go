@@ -0,0 +1,200 @@
+// Package jobstatus derives the status of a job from the copies of its RayJob
+// in the member clusters. Both the API and the job monitor read statuses
+// through it.
+package jobstatus
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/loiht2/ml-platform-training-job/backend/converter"
+	"github.com/loiht2/ml-platform-training-job/backend/karmada"
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// phaseUnknown marks a cluster whose RayJob could not be read
+const phaseUnknown = "Unknown"
+
+// Queue admission states of jobs using Kueue or Volcano
+const (
+	admissionQueued   = "Queued"
+	admissionAdmitted = "Admitted"
+)
+
+// CollectClusterStatuses reads a job's RayJob from every member cluster it is
+// placed on and maps each copy to a platform phase
+func CollectClusterStatuses(ctx context.Context, karmadaClient karmada.Interface, jobName, namespace string) ([]models.ClusterJobStatus, error) {
+	members, err := karmadaClient.GetRayJobsFromMembers(ctx, jobName, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]models.ClusterJobStatus, 0, len(members))
+	for _, member := range members {
+		if member.Err != nil {
+			statuses = append(statuses, models.ClusterJobStatus{
+				Cluster: member.Cluster,
+				Phase:   phaseUnknown,
+				Message: member.Err.Error(),
+			})
+			continue
+		}
+
+		status, _ := member.RayJob["status"].(map[string]interface{})
+		admission := admissionState(ctx, karmadaClient, member.RayJob, member.Cluster, namespace)
+		phase, message := rayJobPhase(status, admission)
+		statuses = append(statuses, models.ClusterJobStatus{
+			Cluster:          member.Cluster,
+			Phase:            phase,
+			Message:          message,
+			Admission:        admission,
+			JobStatus:        getString(status, "jobStatus"),
+			DeploymentStatus: getString(status, "jobDeploymentStatus"),
+			StartTime:        getString(status, "startTime"),
+			EndTime:          getString(status, "endTime"),
+		})
+	}
+
+	return statuses, nil
+}
+
+// AggregateClusterStatuses derives the overall phase of a job from its
+// per-cluster statuses:
+//   - any cluster Failed: Failed
+//   - every cluster Succeeded: Succeeded
+//   - otherwise any cluster Running or Succeeded: Running
+//   - every readable cluster Queued: Queued
+//   - otherwise: Pending
+//
+// Clusters that could not be read never count as succeeded. When no cluster
+// could be read the phase is empty and the last known status should be kept.
+func AggregateClusterStatuses(clusters []models.ClusterJobStatus) (phase, message, admission string) {
+	var failed, succeeded, running, queued, unknown []string
+	for _, cluster := range clusters {
+		switch cluster.Phase {
+		case "Failed":
+			failed = append(failed, cluster.Cluster)
+		case "Succeeded":
+			succeeded = append(succeeded, cluster.Cluster)
+		case "Running":
+			running = append(running, cluster.Cluster)
+		case "Queued":
+			queued = append(queued, cluster.Cluster)
+		case phaseUnknown:
+			unknown = append(unknown, cluster.Cluster)
+		}
+
+		switch {
+		case cluster.Admission == admissionQueued:
+			admission = admissionQueued
+		case cluster.Admission == admissionAdmitted && admission == "":
+			admission = admissionAdmitted
+		}
+	}
+
+	readable := len(clusters) - len(unknown)
+	switch {
+	case readable == 0:
+		return "", "", admission
+	case len(clusters) == 1:
+		// A single copy speaks for itself
+		return clusters[0].Phase, clusters[0].Message, admission
+	case len(failed) > 0:
+		return "Failed", fmt.Sprintf("RayJob failed in %s", strings.Join(failed, ", ")), admission
+	case len(succeeded) == len(clusters):
+		return "Succeeded", fmt.Sprintf("RayJob completed successfully in all %d clusters", len(clusters)), admission
+	case len(running)+len(succeeded) > 0:
+		return "Running", fmt.Sprintf("RayJob is running in %d of %d clusters, completed in %d",
+			len(running), len(clusters), len(succeeded)), admission
+	case len(queued) == readable:
+		return "Queued", "RayJob is waiting for admission by the cluster queues", admission
+	default:
+		return "Pending", fmt.Sprintf("RayJob is pending in %d of %d clusters", readable-len(queued), len(clusters)), admission
+	}
+}
+
+// rayJobPhase maps a RayJob status and its queue admission state to a platform phase
+func rayJobPhase(status map[string]interface{}, admission string) (phase, message string) {
+	// RayJob status has jobStatus and jobDeploymentStatus
+	jobStatus := getString(status, "jobStatus")
+	jobDeploymentStatus := getString(status, "jobDeploymentStatus")
+
+	switch jobStatus {
+	case "SUCCEEDED":
+		phase = "Succeeded"
+		message = "RayJob completed successfully"
+	case "FAILED":
+		phase = "Failed"
+		message = "RayJob failed"
+	case "RUNNING":
+		phase = "Running"
+		message = fmt.Sprintf("RayJob is running (deployment: %s)", jobDeploymentStatus)
+	case "PENDING":
+		phase = "Pending"
+		message = "RayJob is pending"
+	default:
+		if jobDeploymentStatus == "Running" {
+			phase = "Running"
+			message = "RayJob cluster is running"
+		} else {
+			phase = "Pending"
+			message = fmt.Sprintf("RayJob deployment status: %s", jobDeploymentStatus)
+		}
+	}
+
+	// A pending job that is still waiting in a queue is reported as Queued
+	if admission == admissionQueued && phase == "Pending" {
+		phase = "Queued"
+		message = "RayJob is waiting for admission by the cluster queue"
+	}
+
+	return phase, message
+}
+
+// admissionState reports whether a job going through a queue is still waiting
+// (Queued) or has been admitted, and "" for jobs that are not queued or whose
+// state could not be determined
+func admissionState(ctx context.Context, karmadaClient karmada.Interface, rayJob map[string]interface{}, clusterName, namespace string) string {
+	metadata, _ := rayJob["metadata"].(map[string]interface{})
+	labels, _ := metadata["labels"].(map[string]interface{})
+	spec, _ := rayJob["spec"].(map[string]interface{})
+	status, _ := rayJob["status"].(map[string]interface{})
+
+	switch {
+	case getString(labels, converter.KueueQueueNameLabel) != "":
+		// Kueue admits a job by unsuspending it
+		if suspend, _ := spec["suspend"].(bool); suspend {
+			return admissionQueued
+		}
+		return admissionAdmitted
+
+	case getString(labels, converter.RaySchedulerNameLabel) == converter.DefaultVolcanoScheduler:
+		// KubeRay names the PodGroup after the RayCluster it creates for the job
+		rayClusterName := getString(status, "rayClusterName")
+		if rayClusterName == "" {
+			return admissionQueued
+		}
+		phase, err := karmadaClient.GetVolcanoPodGroupPhase(ctx, clusterName, namespace, fmt.Sprintf("ray-%s-pg", rayClusterName))
+		if err != nil {
+			log.Printf("Failed to get PodGroup phase for RayCluster %s in cluster %s: %v", rayClusterName, clusterName, err)
+			return ""
+		}
+		if phase == "Running" || phase == "Completed" {
+			return admissionAdmitted
+		}
+		return admissionQueued
+	}
+
+	return ""
+}
+
+func getString(m map[string]interface{}, key string) string {
+	if v, ok := m[key]; ok {
+		if s, ok := v.(string); ok {
+			return s
+		}
+	}
+	return ""
+}
@@ -0,0 +1,115 @@
+package karmada
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// ResourceQuery selects the objects to list from member clusters
+type ResourceQuery struct {
+	// Group, Version and Resource name the resource type. Group and version
+	// may be left empty, and Resource may carry the group ("rayjobs.ray.io").
+	Group    string
+	Version  string
+	Resource string
+	// Namespace is ignored for cluster-scoped resources; empty means all namespaces
+	Namespace     string
+	LabelSelector string
+	FieldSelector string
+	// Limit and Continue page through large lists; see metav1.ListOptions
+	Limit    int64
+	Continue string
+}
+
+// ListClusterResources lists one page of objects from a member cluster via the Karmada cluster proxy
+func (c *Client) ListClusterResources(ctx context.Context, clusterName string, query ResourceQuery) (*models.ClusterResources, error) {
+	api, err := c.member(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	gvk, namespaced, err := api.kindForQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find resource %s in cluster %s: %w", query.Resource, clusterName, err)
+	}
+
+	namespace := query.Namespace
+	if !namespaced {
+		namespace = ""
+	}
+	list, err := c.ListObjects(ctx, clusterName, gvk, namespace, metav1.ListOptions{
+		LabelSelector: query.LabelSelector,
+		FieldSelector: query.FieldSelector,
+		Limit:         query.Limit,
+		Continue:      query.Continue,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]map[string]interface{}, len(list.Items))
+	for i := range list.Items {
+		resources[i] = list.Items[i].Object
+	}
+	return &models.ClusterResources{
+		Cluster:   clusterName,
+		Kind:      gvk.Kind,
+		Count:     len(resources),
+		Continue:  list.GetContinue(),
+		Resources: resources,
+	}, nil
+}
+
+// ListResourcesInClusters lists objects from several member clusters
+// concurrently. Clusters that fail are reported with their error; the query's
+// Continue token only applies to single clusters and is ignored here.
+func (c *Client) ListResourcesInClusters(ctx context.Context, clusters []string, query ResourceQuery) []models.ClusterResources {
+	query.Continue = ""
+
+	results := make([]models.ClusterResources, len(clusters))
+	var wg sync.WaitGroup
+	for i, clusterName := range clusters {
+		wg.Add(1)
+		go func(i int, clusterName string) {
+			defer wg.Done()
+			resources, err := c.ListClusterResources(ctx, clusterName, query)
+			if err != nil {
+				results[i] = models.ClusterResources{Cluster: clusterName, Error: err.Error()}
+				return
+			}
+			results[i] = *resources
+		}(i, clusterName)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// kindForQuery resolves a partially specified resource type to its kind and scope
+func (a *apiClient) kindForQuery(query ResourceQuery) (schema.GroupVersionKind, bool, error) {
+	gvr := schema.GroupVersionResource{Group: query.Group, Version: query.Version, Resource: query.Resource}
+	if query.Group == "" {
+		gvr = schema.ParseGroupResource(query.Resource).WithVersion(query.Version)
+	}
+
+	gvk, err := a.mapper.KindFor(gvr)
+	if meta.IsNoMatchError(err) {
+		a.mapper.Reset()
+		gvk, err = a.mapper.KindFor(gvr)
+	}
+	if err != nil {
+		return schema.GroupVersionKind{}, false, err
+	}
+
+	mapping, err := a.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return schema.GroupVersionKind{}, false, err
+	}
+	return gvk, mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+}
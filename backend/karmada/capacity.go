@@ -0,0 +1,181 @@
+package karmada
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+const (
+	// GPUResourceName is the extended resource NVIDIA's device plugin advertises
+	GPUResourceName corev1.ResourceName = "nvidia.com/gpu"
+	// GPUProductLabel is set on GPU nodes by NVIDIA GPU feature discovery
+	GPUProductLabel = "nvidia.com/gpu.product"
+)
+
+// ListMemberClusterCapacity lists all member clusters with their capacity.
+// GPU products are read from the ready clusters concurrently.
+func (c *Client) ListMemberClusterCapacity(ctx context.Context) ([]models.ClusterInfo, error) {
+	clusterList, err := c.listClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make([]models.ClusterInfo, len(clusterList))
+	var wg sync.WaitGroup
+	for i := range clusterList {
+		clusters[i] = clusterInfo(clusterList[i])
+		if !clusters[i].Ready {
+			continue
+		}
+
+		wg.Add(1)
+		go func(info *models.ClusterInfo) {
+			defer wg.Done()
+			c.addGPUProducts(ctx, info)
+		}(&clusters[i])
+	}
+	wg.Wait()
+
+	return clusters, nil
+}
+
+// GetClusterCapacity gets one member cluster with its capacity
+func (c *Client) GetClusterCapacity(ctx context.Context, clusterName string) (*models.ClusterInfo, error) {
+	cluster, err := c.getCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	info := clusterInfo(cluster)
+	if info.Ready {
+		c.addGPUProducts(ctx, &info)
+	}
+	return &info, nil
+}
+
+// addGPUProducts aggregates the GPU nodes of a member cluster by product
+func (c *Client) addGPUProducts(ctx context.Context, info *models.ClusterInfo) {
+	if info.Capacity == nil {
+		info.Capacity = &models.ClusterCapacity{}
+	}
+	products, err := c.getGPUProducts(ctx, info.Name)
+	if err != nil {
+		info.Capacity.GPUProductsError = err.Error()
+		return
+	}
+	info.Capacity.GPUProducts = products
+}
+
+func (c *Client) getGPUProducts(ctx context.Context, clusterName string) ([]models.GPUProduct, error) {
+	list, err := c.ListObjects(ctx, clusterName, nodeGVK, "", metav1.ListOptions{LabelSelector: GPUProductLabel})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list GPU nodes: %w", err)
+	}
+
+	var nodes corev1.NodeList
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(list.UnstructuredContent(), &nodes); err != nil {
+		return nil, fmt.Errorf("failed to convert nodes from cluster %s: %w", clusterName, err)
+	}
+
+	byProduct := map[string]*models.GPUProduct{}
+	for _, node := range nodes.Items {
+		product := node.Labels[GPUProductLabel]
+		entry, ok := byProduct[product]
+		if !ok {
+			entry = &models.GPUProduct{Product: product}
+			byProduct[product] = entry
+		}
+		entry.Nodes++
+		if gpus, ok := node.Status.Allocatable[GPUResourceName]; ok {
+			entry.GPUs += gpus.Value()
+		}
+	}
+
+	products := make([]models.GPUProduct, 0, len(byProduct))
+	for _, entry := range byProduct {
+		products = append(products, *entry)
+	}
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].Product < products[j].Product
+	})
+	return products, nil
+}
+
+// clusterInfo summarizes a Karmada Cluster and its resource summary. Location
+// comes from the cluster spec, falling back to the region and zone labels.
+func clusterInfo(cluster *clusterv1alpha1.Cluster) models.ClusterInfo {
+	info := models.ClusterInfo{
+		Name:              cluster.Name,
+		Region:            cluster.Spec.Region,
+		Zone:              cluster.Spec.Zone,
+		Provider:          cluster.Spec.Provider,
+		Labels:            cluster.Labels,
+		KubernetesVersion: cluster.Status.KubernetesVersion,
+		Capacity:          clusterCapacity(cluster),
+		Ready:             clusterReady(cluster),
+	}
+
+	if info.Region == "" {
+		info.Region = cluster.Labels["region"]
+	}
+	if info.Zone == "" {
+		info.Zone = cluster.Labels["zone"]
+	}
+
+	if summary := cluster.Status.NodeSummary; summary != nil {
+		info.Nodes = &models.NodeCounts{
+			Total: summary.TotalNum,
+			Ready: summary.ReadyNum,
+		}
+	}
+
+	return info
+}
+
+// clusterCapacity converts the resource summary Karmada keeps for a cluster,
+// or returns nil when the cluster has not reported one yet
+func clusterCapacity(cluster *clusterv1alpha1.Cluster) *models.ClusterCapacity {
+	summary := cluster.Status.ResourceSummary
+	if summary == nil {
+		return nil
+	}
+	capacity := &models.ClusterCapacity{}
+
+	capacity.Allocatable = resourceAmounts(summary.Allocatable)
+	capacity.Allocated = resourceAmounts(summary.Allocated)
+	capacity.Allocating = resourceAmounts(summary.Allocating)
+	capacity.Available = models.ResourceAmounts{
+		CPUCores:  capacity.Allocatable.CPUCores - capacity.Allocated.CPUCores - capacity.Allocating.CPUCores,
+		MemoryGiB: capacity.Allocatable.MemoryGiB - capacity.Allocated.MemoryGiB - capacity.Allocating.MemoryGiB,
+		GPUs:      capacity.Allocatable.GPUs - capacity.Allocated.GPUs - capacity.Allocating.GPUs,
+		Pods:      capacity.Allocatable.Pods - capacity.Allocated.Pods - capacity.Allocating.Pods,
+	}
+	return capacity
+}
+
+func resourceAmounts(list corev1.ResourceList) models.ResourceAmounts {
+	return models.ResourceAmounts{
+		CPUCores:  float64(quantity(list, corev1.ResourceCPU).MilliValue()) / 1000,
+		MemoryGiB: float64(quantity(list, corev1.ResourceMemory).Value()) / (1 << 30),
+		GPUs:      quantity(list, GPUResourceName).Value(),
+		Pods:      quantity(list, corev1.ResourcePods).Value(),
+	}
+}
+
+func quantity(list corev1.ResourceList, name corev1.ResourceName) *resource.Quantity {
+	if q, ok := list[name]; ok {
+		return &q
+	}
+	return resource.NewQuantity(0, resource.DecimalSI)
+}
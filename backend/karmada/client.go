@@ -2,36 +2,90 @@ package karmada
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 
 	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
 	karmadaclientset "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+
+	"github.com/loiht2/ml-platform-training-job/backend/models"
 )
 
-// Client handles Karmada operations
+// rollBackTimeout bounds deleting the objects of a job that failed to be created
+const rollBackTimeout = 30 * time.Second
+
+// Client handles Karmada operations. Karmada's own APIs use the typed Karmada
+// clientset; workloads and other Kubernetes objects, in the control plane and
+// in member clusters, go through dynamic clients and RESTMappers.
 type Client struct {
 	karmadaClient    *karmadaclientset.Clientset
 	karmadaK8sClient *kubernetes.Clientset
+
+	restConfig *rest.Config
+	karmada    *apiClient
+	members    memberClients
+
+	// inventory caches Clusters and ResourceBindings once StartInventory
+	// succeeded; until then they are read from Karmada
+	inventory atomic.Pointer[Inventory]
 }
 
-// NewClient creates a new Karmada client
-func NewClient(karmadaClient *karmadaclientset.Clientset, k8sClient *kubernetes.Clientset) *Client {
+// PropagationOptions controls how a job's resources are propagated to member clusters
+type PropagationOptions struct {
+	// TargetClusters restricts placement to these clusters; empty means any cluster
+	TargetClusters []string
+	// Priority is the policy priority; when several policies match the same
+	// resource, the one with the highest priority wins
+	Priority *int32
+	// Preemption allows the policy to take over resources already claimed by
+	// lower-priority policies
+	Preemption policyv1alpha1.PreemptionBehavior
+	// Dependencies are other resources the workload needs in the member
+	// clusters, such as its PVC. They are selected by the same policy so they
+	// get the same placement.
+	Dependencies []policyv1alpha1.ResourceSelector
+	// Overrides adapt the workload and its PVC to individual clusters through
+	// OverridePolicies, keyed by cluster name
+	Overrides map[string]models.ClusterOverrides
+	// Placement adds weights, cluster label affinity, tolerations and spread
+	// constraints to the cluster selection
+	Placement *models.Placement
+	// Failover configures how the workload is moved off failed clusters; nil
+	// leaves Karmada's defaults
+	Failover *FailoverOptions
+	// PreferredCluster is the cluster the workload is scheduled to first. It
+	// only moves to one of the FallbackClusters when it cannot be scheduled
+	// there or fails over. Ignored when TargetClusters is set.
+	PreferredCluster string
+	FallbackClusters []string
+}
+
+// NewClient creates a new Karmada client. restConfig is the Karmada API server
+// config the clients were created from; member clusters are reached through
+// its cluster proxy.
+func NewClient(restConfig *rest.Config, karmadaClient *karmadaclientset.Clientset, k8sClient *kubernetes.Clientset, dynamicClient dynamic.Interface) *Client {
 	return &Client{
 		karmadaClient:    karmadaClient,
 		karmadaK8sClient: k8sClient,
+		restConfig:       restConfig,
+		karmada:          newAPIClient(dynamicClient, k8sClient.Discovery()),
 	}
 }
 
 // CreateJobWithPropagationPolicy creates a Kubernetes Job and PropagationPolicy in Karmada
-func (c *Client) CreateJobWithPropagationPolicy(ctx context.Context, job *batchv1.Job, targetClusters []string) error {
+func (c *Client) CreateJobWithPropagationPolicy(ctx context.Context, job *batchv1.Job, opts PropagationOptions) error {
 	// Create the Job in Karmada control plane
 	createdJob, err := c.karmadaK8sClient.BatchV1().Jobs(job.Namespace).Create(ctx, job, metav1.CreateOptions{})
 	if err != nil {
@@ -41,7 +95,11 @@ func (c *Client) CreateJobWithPropagationPolicy(ctx context.Context, job *batchv
 	log.Printf("Created job %s/%s in Karmada control plane", createdJob.Namespace, createdJob.Name)
 
 	// Create PropagationPolicy
-	policy := c.buildPropagationPolicy(job.Name, job.Namespace, targetClusters)
+	policy := c.buildPropagationPolicy(policyv1alpha1.ResourceSelector{
+		APIVersion: "batch/v1",
+		Kind:       "Job",
+		Name:       job.Name,
+	}, job.Namespace, opts)
 	_, err = c.karmadaClient.PolicyV1alpha1().PropagationPolicies(job.Namespace).Create(ctx, policy, metav1.CreateOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to create propagation policy: %w", err)
@@ -51,8 +109,12 @@ func (c *Client) CreateJobWithPropagationPolicy(ctx context.Context, job *batchv
 	return nil
 }
 
-// CreateRayJobWithPropagationPolicy creates a Ray Job and PropagationPolicy in Karmada
-func (c *Client) CreateRayJobWithPropagationPolicy(ctx context.Context, rayJob map[string]interface{}, targetClusters []string) error {
+// CreateRayJobWithPropagationPolicy creates a Ray Job and PropagationPolicy in Karmada.
+// Its OverridePolicies are created first, so no copy of the RayJob is ever
+// propagated without them. When a step fails, the objects created so far are
+// deleted again; those that could not be deleted are returned, so they can be
+// deleted with the job.
+func (c *Client) CreateRayJobWithPropagationPolicy(ctx context.Context, rayJob map[string]interface{}, opts PropagationOptions) ([]models.ResourceRef, error) {
 	// Convert map to unstructured
 	unstructuredObj := &unstructured.Unstructured{
 		Object: rayJob,
@@ -64,88 +126,106 @@ func (c *Client) CreateRayJobWithPropagationPolicy(ctx context.Context, rayJob m
 		unstructuredObj.SetNamespace(namespace)
 	}
 
-	// Create the RayJob using dynamic client
-	gvr := metav1.GroupVersionResource{
-		Group:    "ray.io",
-		Version:  "v1",
-		Resource: "rayjobs",
-	}
-
-	dynamicClient := c.karmadaK8sClient.Discovery().RESTClient()
-	data, err := json.Marshal(unstructuredObj)
+	resources, err := c.createOverridePolicies(ctx, unstructuredObj, opts)
 	if err != nil {
-		return fmt.Errorf("failed to marshal RayJob: %w", err)
+		return c.rollBack(resources), err
 	}
 
-	result := dynamicClient.Post().
-		AbsPath("/apis", gvr.Group, gvr.Version, "namespaces", namespace, gvr.Resource).
-		Body(data).
-		Do(ctx)
-
-	if err := result.Error(); err != nil {
-		return fmt.Errorf("failed to create RayJob in Karmada: %w", err)
+	// Create the RayJob using dynamic client
+	created, err := c.CreateObject(ctx, unstructuredObj)
+	if err != nil {
+		return c.rollBack(resources), err
 	}
+	resources = append(resources, resourceRef(created, created))
 
 	log.Printf("Created RayJob %s/%s in Karmada control plane", namespace, unstructuredObj.GetName())
 
 	// Create PropagationPolicy
-	policy := c.buildPropagationPolicy(unstructuredObj.GetName(), namespace, targetClusters)
-	policy.Spec.ResourceSelectors = []policyv1alpha1.ResourceSelector{
-		{
-			APIVersion: "ray.io/v1",
-			Kind:       "RayJob",
-			Name:       unstructuredObj.GetName(),
-		},
-	}
+	policy := c.buildPropagationPolicy(policyv1alpha1.ResourceSelector{
+		APIVersion: rayJobGVK.GroupVersion().String(),
+		Kind:       rayJobGVK.Kind,
+		Name:       unstructuredObj.GetName(),
+	}, namespace, opts)
 
 	_, err = c.karmadaClient.PolicyV1alpha1().PropagationPolicies(namespace).Create(ctx, policy, metav1.CreateOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to create propagation policy: %w", err)
+		return c.rollBack(resources), fmt.Errorf("failed to create propagation policy: %w", err)
 	}
+	resources = append(resources, resourceRef(&policy.TypeMeta, &policy.ObjectMeta))
 
 	log.Printf("Created propagation policy %s/%s", policy.Namespace, policy.Name)
-	return nil
+	return resources, nil
 }
 
-// buildPropagationPolicy creates a PropagationPolicy for distributing resources
-func (c *Client) buildPropagationPolicy(resourceName, namespace string, targetClusters []string) *policyv1alpha1.PropagationPolicy {
+// rollBack deletes the objects of a job that failed to be created, newest
+// first, and returns those that could not be deleted
+func (c *Client) rollBack(resources []models.ResourceRef) []models.ResourceRef {
+	// The request context may be what ran out
+	ctx, cancel := context.WithTimeout(context.Background(), rollBackTimeout)
+	defer cancel()
+
+	var remaining []models.ResourceRef
+	background := metav1.DeletePropagationBackground
+	for i := len(resources) - 1; i >= 0; i-- {
+		ref := resources[i]
+		err := c.DeleteObject(ctx, refGVK(ref), ref.Namespace, ref.Name, metav1.DeleteOptions{PropagationPolicy: &background})
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Printf("Failed to roll back %s %s/%s: %v", ref.Kind, ref.Namespace, ref.Name, err)
+			remaining = append([]models.ResourceRef{ref}, remaining...)
+			continue
+		}
+		log.Printf("Rolled back %s %s/%s", ref.Kind, ref.Namespace, ref.Name)
+	}
+	return remaining
+}
+
+// buildPropagationPolicy creates a PropagationPolicy distributing a workload and its dependencies
+func (c *Client) buildPropagationPolicy(workload policyv1alpha1.ResourceSelector, namespace string, opts PropagationOptions) *policyv1alpha1.PropagationPolicy {
 	clusterAffinity := &policyv1alpha1.ClusterAffinity{}
 
-	if len(targetClusters) > 0 {
+	if len(opts.TargetClusters) > 0 {
 		// Target specific clusters
-		clusterNames := make([]string, len(targetClusters))
-		copy(clusterNames, targetClusters)
+		clusterNames := make([]string, len(opts.TargetClusters))
+		copy(clusterNames, opts.TargetClusters)
 		clusterAffinity.ClusterNames = clusterNames
 	} else {
 		// Target all clusters
 		clusterAffinity.ClusterNames = []string{}
 	}
 
-	return &policyv1alpha1.PropagationPolicy{
+	// The workload comes first, followed by its dependencies
+	resourceSelectors := append([]policyv1alpha1.ResourceSelector{workload}, opts.Dependencies...)
+
+	placement := policyv1alpha1.Placement{
+		ClusterAffinity: clusterAffinity,
+		ReplicaScheduling: &policyv1alpha1.ReplicaSchedulingStrategy{
+			ReplicaSchedulingType: policyv1alpha1.ReplicaSchedulingTypeDivided,
+		},
+	}
+	applyPlacementOptions(&placement, opts.Placement)
+	if opts.PreferredCluster != "" && len(opts.TargetClusters) == 0 {
+		preferCluster(&placement, opts.PreferredCluster, opts.FallbackClusters)
+	}
+
+	policy := &policyv1alpha1.PropagationPolicy{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "policy.karmada.io/v1alpha1",
 			Kind:       "PropagationPolicy",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-propagation", resourceName),
+			Name:      fmt.Sprintf("%s-propagation", workload.Name),
 			Namespace: namespace,
 		},
 		Spec: policyv1alpha1.PropagationSpec{
-			ResourceSelectors: []policyv1alpha1.ResourceSelector{
-				{
-					APIVersion: "batch/v1",
-					Kind:       "Job",
-					Name:       resourceName,
-				},
-			},
-			Placement: policyv1alpha1.Placement{
-				ClusterAffinity: clusterAffinity,
-				ReplicaScheduling: &policyv1alpha1.ReplicaSchedulingStrategy{
-					ReplicaSchedulingType: policyv1alpha1.ReplicaSchedulingTypeDivided,
-				},
-			},
+			ResourceSelectors: resourceSelectors,
+			Placement:         placement,
+			Priority:          opts.Priority,
+			Preemption:        opts.Preemption,
 		},
 	}
+	applyFailover(&policy.Spec, opts.Failover)
+
+	return policy
 }
 
 // GetJobStatus retrieves job status from Karmada control plane
@@ -157,211 +237,138 @@ func (c *Client) GetJobStatus(ctx context.Context, name, namespace string) (*bat
 	return job, nil
 }
 
-// DeleteJob deletes a job and its propagation policy
-func (c *Client) DeleteJob(ctx context.Context, name, namespace string) error {
-	// Delete the job
-	err := c.karmadaK8sClient.BatchV1().Jobs(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+// ListMemberClusters lists all member clusters registered in Karmada with their resource summary
+func (c *Client) ListMemberClusters(ctx context.Context) ([]models.ClusterInfo, error) {
+	clusterList, err := c.listClusters(ctx)
 	if err != nil {
-		log.Printf("Warning: failed to delete job: %v", err)
+		return nil, err
 	}
 
-	// Delete the propagation policy
-	policyName := fmt.Sprintf("%s-propagation", name)
-	err = c.karmadaClient.PolicyV1alpha1().PropagationPolicies(namespace).Delete(ctx, policyName, metav1.DeleteOptions{})
-	if err != nil {
-		log.Printf("Warning: failed to delete propagation policy: %v", err)
+	clusters := make([]models.ClusterInfo, 0, len(clusterList))
+	for _, cluster := range clusterList {
+		clusters = append(clusters, clusterInfo(cluster))
 	}
 
-	return nil
+	return clusters, nil
 }
 
-// ListMemberClusters lists all member clusters registered in Karmada
-func (c *Client) ListMemberClusters(ctx context.Context) ([]map[string]interface{}, error) {
-	clusterList, err := c.karmadaClient.ClusterV1alpha1().Clusters().List(ctx, metav1.ListOptions{})
+// CreatePVC creates a PersistentVolumeClaim in Karmada control plane
+func (c *Client) CreatePVC(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (models.ResourceRef, error) {
+	created, err := c.CreateObject(ctx, pvc)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list member clusters: %w", err)
+		return models.ResourceRef{}, err
 	}
 
-	clusters := make([]map[string]interface{}, 0, len(clusterList.Items))
-	for _, cluster := range clusterList.Items {
-		clusterInfo := map[string]interface{}{
-			"name":  cluster.Name,
-			"ready": false,
-		}
+	log.Printf("Created PVC %s/%s in Karmada control plane", pvc.Namespace, pvc.Name)
+	return resourceRef(created, created), nil
+}
 
-		// Check if cluster is ready
-		for _, condition := range cluster.Status.Conditions {
-			if condition.Type == "Ready" && condition.Status == "True" {
-				clusterInfo["ready"] = true
-				break
-			}
-		}
+// CreateConfigMap creates a ConfigMap in Karmada control plane
+func (c *Client) CreateConfigMap(ctx context.Context, configMap *corev1.ConfigMap) (models.ResourceRef, error) {
+	created, err := c.CreateObject(ctx, configMap)
+	if err != nil {
+		return models.ResourceRef{}, err
+	}
 
-		// Add labels if available
-		if cluster.Labels != nil {
-			if region, ok := cluster.Labels["region"]; ok {
-				clusterInfo["region"] = region
-			}
-			if zone, ok := cluster.Labels["zone"]; ok {
-				clusterInfo["zone"] = zone
-			}
-		}
+	log.Printf("Created ConfigMap %s/%s in Karmada control plane", configMap.Namespace, configMap.Name)
+	return resourceRef(created, created), nil
+}
 
-		clusters = append(clusters, clusterInfo)
+// CreateSecret creates a Secret in Karmada control plane
+func (c *Client) CreateSecret(ctx context.Context, secret *corev1.Secret) (models.ResourceRef, error) {
+	created, err := c.CreateObject(ctx, secret)
+	if err != nil {
+		return models.ResourceRef{}, err
 	}
 
-	return clusters, nil
+	log.Printf("Created Secret %s/%s in Karmada control plane", secret.Namespace, secret.Name)
+	return resourceRef(created, created), nil
 }
 
-// GetClusterResources gets resources from a specific member cluster via Karmada aggregated API
-func (c *Client) GetClusterResources(ctx context.Context, clusterName, namespace, resourceType string) ([]runtime.Object, error) {
-	// Use Karmada's cluster proxy to access member cluster resources
-	// Path format: /apis/cluster.karmada.io/v1alpha1/clusters/{cluster}/proxy/api/v1/namespaces/{namespace}/{resourceType}
-	
-	restClient := c.karmadaK8sClient.Discovery().RESTClient()
-	
-	path := fmt.Sprintf("/apis/cluster.karmada.io/v1alpha1/clusters/%s/proxy/api/v1/namespaces/%s/%s", 
-		clusterName, namespace, resourceType)
-	
-	result := restClient.Get().AbsPath(path).Do(ctx)
-	
-	if err := result.Error(); err != nil {
-		return nil, fmt.Errorf("failed to get resources from cluster %s: %w", clusterName, err)
-	}
+// MemberRayJob is a job's RayJob as read from one member cluster
+type MemberRayJob struct {
+	Cluster string
+	RayJob  map[string]interface{}
+	// Err is set when the RayJob could not be read from the cluster
+	Err error
+}
 
-	data, err := result.Raw()
+// GetRayJobStatusFromMembers gets the RayJob status from every member cluster the job is placed on, keyed by cluster.
+// Clusters the status could not be read from are left out.
+func (c *Client) GetRayJobStatusFromMembers(ctx context.Context, name, namespace string) (map[string]map[string]interface{}, error) {
+	members, err := c.GetRayJobsFromMembers(ctx, name, namespace)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get raw data: %w", err)
-	}
-
-	// Parse the response
-	var objList unstructured.UnstructuredList
-	if err := json.Unmarshal(data, &objList); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		return nil, err
 	}
 
-	objects := make([]runtime.Object, len(objList.Items))
-	for i := range objList.Items {
-		objects[i] = &objList.Items[i]
+	statuses := make(map[string]map[string]interface{}, len(members))
+	for _, member := range members {
+		if member.Err != nil {
+			continue
+		}
+		status, ok := member.RayJob["status"].(map[string]interface{})
+		if !ok {
+			status = map[string]interface{}{}
+		}
+		statuses[member.Cluster] = status
 	}
 
-	return objects, nil
+	return statuses, nil
 }
 
-// CreatePVC creates a PersistentVolumeClaim in Karmada control plane
-func (c *Client) CreatePVC(ctx context.Context, pvc interface{}) error {
-	// Handle both *corev1.PersistentVolumeClaim and map types
-	var namespace, name string
-	var data []byte
-	var err error
-	
-	switch v := pvc.(type) {
-	case map[string]interface{}:
-		// Unstructured format
-		metadata := v["metadata"].(map[string]interface{})
-		namespace = metadata["namespace"].(string)
-		name = metadata["name"].(string)
-		data, err = json.Marshal(v)
-	default:
-		// Assume it's a typed PVC, marshal it
-		data, err = json.Marshal(pvc)
-		if err != nil {
-			return fmt.Errorf("failed to marshal PVC: %w", err)
-		}
-		
-		// Parse to get namespace and name
-		var obj map[string]interface{}
-		if err := json.Unmarshal(data, &obj); err != nil {
-			return fmt.Errorf("failed to unmarshal PVC: %w", err)
-		}
-		metadata := obj["metadata"].(map[string]interface{})
-		namespace = metadata["namespace"].(string)
-		name = metadata["name"].(string)
+// GetRayJobsFromMembers reads the RayJob from every member cluster the job is placed on.
+// The clusters are queried concurrently; per-cluster failures are reported in MemberRayJob.Err.
+// A RayJob Karmada has not scheduled yet fails with a *NotScheduledError.
+func (c *Client) GetRayJobsFromMembers(ctx context.Context, name, namespace string) ([]MemberRayJob, error) {
+	binding, err := c.GetRayJobBinding(ctx, name, namespace)
+	if apierrors.IsNotFound(err) {
+		return nil, &NotScheduledError{Name: name}
 	}
-	
 	if err != nil {
-		return fmt.Errorf("failed to prepare PVC: %w", err)
-	}
-	
-	// Create PVC using REST client
-	restClient := c.karmadaK8sClient.Discovery().RESTClient()
-	result := restClient.Post().
-		AbsPath("/api/v1/namespaces", namespace, "persistentvolumeclaims").
-		Body(data).
-		Do(ctx)
-	
-	if err := result.Error(); err != nil {
-		return fmt.Errorf("failed to create PVC in Karmada: %w", err)
-	}
-	
-	log.Printf("Created PVC %s/%s in Karmada control plane", namespace, name)
-	return nil
-}
-
-// GetRayJobStatusFromMembers gets RayJob status from member clusters via Karmada aggregated API
-func (c *Client) GetRayJobStatusFromMembers(ctx context.Context, name, namespace string) (map[string]interface{}, error) {
-	// First, get the list of clusters where this job is deployed
-	clusters, err := c.getJobDeploymentClusters(ctx, name, namespace)
-	if err != nil || len(clusters) == 0 {
 		return nil, fmt.Errorf("failed to find deployment clusters for job %s: %w", name, err)
 	}
-
-	// Query the first cluster for job status (all replicas should have same status)
-	clusterName := clusters[0]
-	
-	restClient := c.karmadaK8sClient.Discovery().RESTClient()
-	path := fmt.Sprintf("/apis/cluster.karmada.io/v1alpha1/clusters/%s/proxy/apis/ray.io/v1/namespaces/%s/rayjobs/%s",
-		clusterName, namespace, name)
-	
-	result := restClient.Get().AbsPath(path).Do(ctx)
-	
-	if err := result.Error(); err != nil {
-		return nil, fmt.Errorf("failed to get RayJob status from cluster %s: %w", clusterName, err)
+	if len(binding.Spec.Clusters) == 0 {
+		return nil, &NotScheduledError{Name: name, Message: schedulingMessage(binding)}
 	}
-
-	data, err := result.Raw()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get raw data: %w", err)
+	clusters := make([]string, 0, len(binding.Spec.Clusters))
+	for _, target := range binding.Spec.Clusters {
+		clusters = append(clusters, target.Name)
 	}
 
-	// Parse the response
-	var rayJob map[string]interface{}
-	if err := json.Unmarshal(data, &rayJob); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal RayJob: %w", err)
+	members := make([]MemberRayJob, len(clusters))
+	var wg sync.WaitGroup
+	for i, clusterName := range clusters {
+		wg.Add(1)
+		go func(i int, clusterName string) {
+			defer wg.Done()
+			rayJob, err := c.getMemberRayJob(ctx, clusterName, name, namespace)
+			members[i] = MemberRayJob{Cluster: clusterName, RayJob: rayJob, Err: err}
+		}(i, clusterName)
 	}
+	wg.Wait()
 
-	// Extract status
-	if status, ok := rayJob["status"].(map[string]interface{}); ok {
-		return status, nil
-	}
+	return members, nil
+}
 
-	return map[string]interface{}{}, nil
+// getMemberRayJob gets a RayJob from a member cluster through the Karmada cluster proxy
+func (c *Client) getMemberRayJob(ctx context.Context, clusterName, name, namespace string) (map[string]interface{}, error) {
+	rayJob, err := c.GetObject(ctx, clusterName, rayJobGVK, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return rayJob.Object, nil
 }
 
-// getJobDeploymentClusters gets the list of clusters where a job is deployed
+// getJobDeploymentClusters gets the list of clusters Karmada scheduled a job's RayJob to
 func (c *Client) getJobDeploymentClusters(ctx context.Context, name, namespace string) ([]string, error) {
-	// Get the propagation policy
-	policyName := fmt.Sprintf("%s-propagation", name)
-	policy, err := c.karmadaClient.PolicyV1alpha1().PropagationPolicies(namespace).Get(ctx, policyName, metav1.GetOptions{})
+	binding, err := c.GetRayJobBinding(ctx, name, namespace)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get propagation policy: %w", err)
+		return nil, err
 	}
 
-	// Extract target clusters
-	clusters := policy.Spec.Placement.ClusterAffinity.ClusterNames
-	if len(clusters) == 0 {
-		// If no specific clusters, get all ready clusters
-		allClusters, err := c.ListMemberClusters(ctx)
-		if err != nil {
-			return nil, err
-		}
-		for _, cluster := range allClusters {
-			if ready, ok := cluster["ready"].(bool); ok && ready {
-				if name, ok := cluster["name"].(string); ok {
-					clusters = append(clusters, name)
-				}
-			}
-		}
+	clusters := make([]string, 0, len(binding.Spec.Clusters))
+	for _, target := range binding.Spec.Clusters {
+		clusters = append(clusters, target.Name)
 	}
 
 	return clusters, nil
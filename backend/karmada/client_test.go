@@ -0,0 +1,53 @@
+package karmada
+
+import (
+	"reflect"
+	"testing"
+
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+func TestBuildPropagationPolicySelectsWorkloadAndDependencies(t *testing.T) {
+	pvc := policyv1alpha1.ResourceSelector{APIVersion: "v1", Kind: "PersistentVolumeClaim", Name: "job-pvc"}
+	rayJob := policyv1alpha1.ResourceSelector{APIVersion: "ray.io/v1", Kind: "RayJob", Name: "job"}
+
+	policy := (&Client{}).buildPropagationPolicy(rayJob, "ml", PropagationOptions{Dependencies: []policyv1alpha1.ResourceSelector{pvc}})
+
+	if policy.Name != "job-propagation" || policy.Namespace != "ml" {
+		t.Errorf("policy = %s/%s, want ml/job-propagation", policy.Namespace, policy.Name)
+	}
+	selectors := policy.Spec.ResourceSelectors
+	if len(selectors) != 2 || selectors[0] != rayJob || selectors[1] != pvc {
+		t.Errorf("resource selectors = %+v, want the RayJob then its PVC", selectors)
+	}
+}
+
+func TestBuildPropagationPolicyPrefersClusterWithinLabelAffinity(t *testing.T) {
+	rayJob := policyv1alpha1.ResourceSelector{APIVersion: "ray.io/v1", Kind: "RayJob", Name: "job"}
+	labels := map[string]string{"gpu": "a100"}
+
+	policy := (&Client{}).buildPropagationPolicy(rayJob, "ml", PropagationOptions{
+		Placement:        &models.Placement{ClusterLabels: labels},
+		PreferredCluster: "member-1",
+		FallbackClusters: []string{"member-2"},
+	})
+
+	placement := policy.Spec.Placement
+	if placement.ClusterAffinity != nil {
+		t.Errorf("cluster affinity = %+v, want it replaced by the affinity groups", placement.ClusterAffinity)
+	}
+	want := [][]string{{"member-1"}, {"member-2"}}
+	if len(placement.ClusterAffinities) != len(want) {
+		t.Fatalf("got %d affinity groups, want %d", len(placement.ClusterAffinities), len(want))
+	}
+	for i, term := range placement.ClusterAffinities {
+		if !reflect.DeepEqual(term.ClusterNames, want[i]) {
+			t.Errorf("group %s clusters = %v, want %v", term.AffinityName, term.ClusterNames, want[i])
+		}
+		if term.LabelSelector == nil || !reflect.DeepEqual(term.LabelSelector.MatchLabels, labels) {
+			t.Errorf("group %s label selector = %+v, want %v", term.AffinityName, term.LabelSelector, labels)
+		}
+	}
+}
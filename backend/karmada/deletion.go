@@ -0,0 +1,162 @@
+package karmada
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	"github.com/karmada-io/karmada/pkg/util/names"
+
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// deletionPollInterval is how often DeleteJobResources checks whether deleted objects are gone
+const deletionPollInterval = 2 * time.Second
+
+// resourceRef identifies an object created for a job
+func resourceRef(kind schema.ObjectKind, obj metav1.Object) models.ResourceRef {
+	apiVersion, kindName := kind.GroupVersionKind().ToAPIVersionAndKind()
+	return models.ResourceRef{
+		APIVersion: apiVersion,
+		Kind:       kindName,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+}
+
+// ExpectedJobResources returns the objects created for a job before they
+// were recorded: its RayJob, propagation and override policies, and its own
+// PVC if pvcName is set. Objects that were never created are skipped on delete.
+func ExpectedJobResources(jobName, namespace, pvcName string) []models.ResourceRef {
+	policyAPIVersion := policyv1alpha1.SchemeGroupVersion.String()
+	resources := []models.ResourceRef{
+		{APIVersion: rayJobGVK.GroupVersion().String(), Kind: rayJobGVK.Kind, Namespace: namespace, Name: jobName},
+	}
+	if pvcName != "" {
+		resources = append(resources, models.ResourceRef{APIVersion: pvcGVK.GroupVersion().String(), Kind: pvcGVK.Kind, Namespace: namespace, Name: pvcName})
+	}
+	return append(resources,
+		models.ResourceRef{APIVersion: policyAPIVersion, Kind: "PropagationPolicy", Namespace: namespace, Name: fmt.Sprintf("%s-propagation", jobName)},
+		models.ResourceRef{APIVersion: policyAPIVersion, Kind: "OverridePolicy", Namespace: namespace, Name: overridePolicyName(jobName)},
+		models.ResourceRef{APIVersion: policyAPIVersion, Kind: "OverridePolicy", Namespace: namespace, Name: pvcOverridePolicyName(jobName)},
+	)
+}
+
+// DeleteJobResources deletes a job's objects from Karmada with foreground
+// propagation and waits, until ctx is done, for them and their copies in the
+// member clusters to be gone. Policies are deleted last, so the copies stay
+// governed by them while they are removed. Objects already gone count as
+// deleted. The results are in the order of resources.
+func (c *Client) DeleteJobResources(ctx context.Context, resources []models.ResourceRef) []models.DeletionResult {
+	results := make([]models.DeletionResult, len(resources))
+	placed := make([][]string, len(resources))
+	for i, ref := range resources {
+		results[i] = models.DeletionResult{ResourceRef: ref}
+		// The binding listing the member clusters goes away with the object
+		if !isPolicy(ref) {
+			placed[i] = c.boundClusters(ctx, ref)
+		}
+	}
+
+	order := make([]int, len(resources))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return !isPolicy(resources[order[a]]) && isPolicy(resources[order[b]])
+	})
+
+	foreground := metav1.DeletePropagationForeground
+	for _, i := range order {
+		ref := resources[i]
+		err := c.DeleteObject(ctx, refGVK(ref), ref.Namespace, ref.Name, metav1.DeleteOptions{PropagationPolicy: &foreground})
+		if err != nil && !apierrors.IsNotFound(err) {
+			results[i].Result = models.DeletionFailed
+			results[i].Error = err.Error()
+			log.Printf("Failed to delete %s %s/%s: %v", ref.Kind, ref.Namespace, ref.Name, err)
+		}
+	}
+
+	_ = wait.PollUntilContextCancel(ctx, deletionPollInterval, true, func(ctx context.Context) (bool, error) {
+		done := true
+		for i, ref := range resources {
+			if results[i].Result != "" {
+				continue
+			}
+			remaining, err := c.remainingCopies(ctx, ref, placed[i])
+			results[i].Clusters = remaining
+			results[i].Error = ""
+			if err != nil {
+				results[i].Error = err.Error()
+			}
+			if err == nil && len(remaining) == 0 {
+				results[i].Result = models.DeletionDeleted
+				continue
+			}
+			done = false
+		}
+		return done, nil
+	})
+
+	for i := range results {
+		if results[i].Result == "" {
+			results[i].Result = models.DeletionPending
+		}
+	}
+	return results
+}
+
+// remainingCopies returns the member clusters still holding a copy of an
+// object. An error means the object still exists in Karmada or a cluster
+// could not be checked.
+func (c *Client) remainingCopies(ctx context.Context, ref models.ResourceRef, clusters []string) ([]string, error) {
+	gvk := refGVK(ref)
+	if _, err := c.GetObject(ctx, "", gvk, ref.Namespace, ref.Name); err == nil {
+		return clusters, fmt.Errorf("%s %s/%s is still being deleted in Karmada", ref.Kind, ref.Namespace, ref.Name)
+	} else if !apierrors.IsNotFound(err) {
+		return clusters, err
+	}
+
+	var remaining []string
+	var lastErr error
+	for _, cluster := range clusters {
+		_, err := c.GetObject(ctx, cluster, gvk, ref.Namespace, ref.Name)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		remaining = append(remaining, cluster)
+		if err != nil {
+			lastErr = err
+		}
+	}
+	return remaining, lastErr
+}
+
+// boundClusters returns the member clusters Karmada scheduled an object to, if any
+func (c *Client) boundClusters(ctx context.Context, ref models.ResourceRef) []string {
+	binding, err := c.getResourceBinding(ctx, ref.Namespace, names.GenerateBindingName(ref.Kind, ref.Name))
+	if err != nil {
+		return nil
+	}
+	clusters := make([]string, 0, len(binding.Spec.Clusters))
+	for _, target := range binding.Spec.Clusters {
+		clusters = append(clusters, target.Name)
+	}
+	return clusters
+}
+
+func isPolicy(ref models.ResourceRef) bool {
+	return refGVK(ref).Group == policyv1alpha1.GroupName
+}
+
+func refGVK(ref models.ResourceRef) schema.GroupVersionKind {
+	return schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind)
+}
@@ -0,0 +1,200 @@
+package karmada
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+
+	"github.com/karmada-io/karmada/pkg/util/names"
+
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// rayClusterLabel is the label KubeRay puts on the pods of a RayCluster
+const rayClusterLabel = "ray.io/cluster"
+
+// eventListPageSize is how many events one list request returns at most
+const eventListPageSize = 250
+
+// eventObject identifies an object whose events belong to a job
+type eventObject struct {
+	kind string
+	name string
+}
+
+// GetJobEvents collects the events of a job's objects into one timeline,
+// oldest first: the RayJob and its ResourceBinding in the Karmada control
+// plane, the Works propagating the RayJob, and in every member cluster the
+// RayJob is placed on the RayJob, its RayCluster, its submitter Job and the
+// head and worker pods. Clusters whose events could not be read are returned
+// in errs, keyed by cluster name; Karmada under models.KarmadaEventSource.
+func (c *Client) GetJobEvents(ctx context.Context, name, namespace string) (events []models.JobEvent, errs map[string]error) {
+	errs = map[string]error{}
+	var mu sync.Mutex
+	collect := func(cluster string, clusterEvents []models.JobEvent, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[cluster] = err
+		}
+		events = append(events, clusterEvents...)
+	}
+
+	// Not scheduled yet: the control plane events say why
+	clusters, _ := c.getJobDeploymentClusters(ctx, name, namespace)
+
+	controlPlaneEvents, err := c.karmadaEvents(ctx, name, namespace, clusters)
+	collect(models.KarmadaEventSource, controlPlaneEvents, err)
+
+	var wg sync.WaitGroup
+	for _, cluster := range clusters {
+		wg.Add(1)
+		go func(cluster string) {
+			defer wg.Done()
+			memberEvents, err := c.memberEvents(ctx, cluster, name, namespace)
+			collect(cluster, memberEvents, err)
+		}(cluster)
+	}
+	wg.Wait()
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+	return events, errs
+}
+
+// karmadaEvents reads the events of a RayJob, its ResourceBinding and its Works from the control plane
+func (c *Client) karmadaEvents(ctx context.Context, name, namespace string, clusters []string) ([]models.JobEvent, error) {
+	events, err := c.listEvents(ctx, "", namespace, models.KarmadaEventSource, map[eventObject]bool{
+		{kind: rayJobKind, name: name}: true,
+		{kind: "ResourceBinding", name: names.GenerateBindingName(rayJobKind, name)}: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	workName := names.GenerateWorkName(rayJobKind, name, namespace)
+	for _, cluster := range clusters {
+		workEvents, err := c.listEvents(ctx, "", names.GenerateExecutionSpaceName(cluster), models.KarmadaEventSource, map[eventObject]bool{
+			{kind: "Work", name: workName}: true,
+		})
+		if err != nil {
+			return events, err
+		}
+		events = append(events, workEvents...)
+	}
+	return events, nil
+}
+
+// memberEvents reads the events of a job's RayJob, RayCluster, submitter Job and pods from a member cluster
+func (c *Client) memberEvents(ctx context.Context, cluster, name, namespace string) ([]models.JobEvent, error) {
+	objects := map[eventObject]bool{
+		{kind: rayJobKind, name: name}: true,
+		{kind: "Job", name: name}:      true,
+	}
+
+	// Not propagated yet: only the events of the RayJob itself, if any
+	var rayClusterName string
+	rayJob, err := c.GetObject(ctx, cluster, rayJobGVK, namespace, name)
+	switch {
+	case err == nil:
+		rayClusterName, _, _ = unstructured.NestedString(rayJob.Object, "status", "rayClusterName")
+	case !apierrors.IsNotFound(err):
+		return nil, err
+	}
+	if rayClusterName != "" {
+		objects[eventObject{kind: "RayCluster", name: rayClusterName}] = true
+
+		pods, err := c.ListObjects(ctx, cluster, podGVK, namespace, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", rayClusterLabel, rayClusterName),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, pod := range pods.Items {
+			objects[eventObject{kind: "Pod", name: pod.GetName()}] = true
+		}
+	}
+
+	return c.listEvents(ctx, cluster, namespace, cluster, objects)
+}
+
+// listEvents lists the events in a namespace about the given objects, tagged
+// with source. Each object's events are listed through a field selector, a
+// page at a time, so busy namespaces are never listed whole.
+func (c *Client) listEvents(ctx context.Context, cluster, namespace, source string, objects map[eventObject]bool) ([]models.JobEvent, error) {
+	involvedObjects := make([]eventObject, 0, len(objects))
+	for object := range objects {
+		involvedObjects = append(involvedObjects, object)
+	}
+	sort.Slice(involvedObjects, func(i, j int) bool {
+		a, b := involvedObjects[i], involvedObjects[j]
+		if a.kind != b.kind {
+			return a.kind < b.kind
+		}
+		return a.name < b.name
+	})
+
+	var events []models.JobEvent
+	for _, object := range involvedObjects {
+		opts := metav1.ListOptions{
+			FieldSelector: fields.Set{"involvedObject.kind": object.kind, "involvedObject.name": object.name}.String(),
+			Limit:         eventListPageSize,
+		}
+		for {
+			list, err := c.ListObjects(ctx, cluster, eventGVK, namespace, opts)
+			if err != nil {
+				return events, err
+			}
+			for i := range list.Items {
+				var event corev1.Event
+				if err := fromUnstructured(&list.Items[i], &event); err != nil {
+					return events, err
+				}
+				involved := event.InvolvedObject
+				if involved.Kind != object.kind || involved.Name != object.name {
+					continue
+				}
+				events = append(events, models.JobEvent{
+					Time:    eventTime(&event),
+					Cluster: source,
+					Kind:    involved.Kind,
+					Name:    involved.Name,
+					Type:    event.Type,
+					Reason:  event.Reason,
+					Message: event.Message,
+					Count:   event.Count,
+				})
+			}
+			if opts.Continue = list.GetContinue(); opts.Continue == "" {
+				break
+			}
+		}
+	}
+	return events, nil
+}
+
+// eventTime returns when an event last occurred. Events recorded through the
+// events.k8s.io API only set eventTime.
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
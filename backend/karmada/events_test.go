@@ -0,0 +1,95 @@
+package karmada
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func eventAbout(kind, name, reason string) unstructured.Unstructured {
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "ml", Name: name + "." + reason},
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Name: name},
+		Reason:         reason,
+	}
+	content, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(event)
+	return unstructured.Unstructured{Object: content}
+}
+
+func TestListEventsSelectsAndPagesEachObject(t *testing.T) {
+	discovery := k8sfake.NewSimpleClientset().Discovery().(*fakediscovery.FakeDiscovery)
+	discovery.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{{Name: "events", Namespaced: true, Kind: "Event"}},
+	}}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{{Version: "v1", Resource: "events"}: "EventList"})
+
+	// The RayJob's events come in two pages; the API server would filter by
+	// field, the fake answers every list with all events of its page
+	pages := map[string][][]unstructured.Unstructured{
+		"involvedObject.kind=RayJob,involvedObject.name=job": {
+			{eventAbout("RayJob", "job", "Created"), eventAbout("Pod", "job-head", "Pulled")},
+			{eventAbout("RayJob", "job", "Completed")},
+		},
+		"involvedObject.kind=Pod,involvedObject.name=job-head": {
+			{eventAbout("Pod", "job-head", "Scheduled")},
+		},
+	}
+	var selectors []string
+	dynamicClient.PrependReactor("list", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		selector := action.(k8stesting.ListAction).GetListRestrictions().Fields.String()
+		selectors = append(selectors, selector)
+		remaining := pages[selector]
+		list := &unstructured.UnstructuredList{Object: map[string]interface{}{"apiVersion": "v1", "kind": "EventList"}}
+		if len(remaining) > 0 {
+			list.Items = remaining[0]
+			pages[selector] = remaining[1:]
+		}
+		if len(pages[selector]) > 0 {
+			list.SetContinue("next")
+		}
+		return true, list, nil
+	})
+
+	c := &Client{karmada: newAPIClient(dynamicClient, discovery)}
+	events, err := c.listEvents(context.Background(), "", "ml", "karmada", map[eventObject]bool{
+		{kind: "RayJob", name: "job"}:    true,
+		{kind: "Pod", name: "job-head"}:  true,
+		{kind: "Job", name: "submitter"}: true,
+	})
+	if err != nil {
+		t.Fatalf("listEvents: %v", err)
+	}
+
+	want := []string{
+		"involvedObject.kind=Job,involvedObject.name=submitter",
+		"involvedObject.kind=Pod,involvedObject.name=job-head",
+		"involvedObject.kind=RayJob,involvedObject.name=job",
+		"involvedObject.kind=RayJob,involvedObject.name=job",
+	}
+	if !reflect.DeepEqual(selectors, want) {
+		t.Errorf("field selectors = %v, want %v", selectors, want)
+	}
+
+	var reasons []string
+	for _, event := range events {
+		reasons = append(reasons, event.Kind+"/"+event.Reason)
+	}
+	sort.Strings(reasons)
+	wantReasons := []string{"Pod/Scheduled", "RayJob/Completed", "RayJob/Created"}
+	if !reflect.DeepEqual(reasons, wantReasons) {
+		t.Errorf("events = %v, want %v", reasons, wantReasons)
+	}
+}
@@ -0,0 +1,80 @@
+package karmada
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+)
+
+// Platform failover defaults
+const (
+	DefaultClusterTolerationSeconds   int32 = 60
+	DefaultUnhealthyTolerationSeconds int32 = 300
+	DefaultFailoverGracePeriodSeconds int32 = 600
+)
+
+// FailoverOptions controls how Karmada moves a job off failed clusters
+type FailoverOptions struct {
+	// Disabled keeps the job on its clusters, however long they are down
+	Disabled bool
+	// ClusterTolerationSeconds is how long the job stays on a NotReady or
+	// unreachable cluster before Karmada evicts it and schedules it elsewhere
+	ClusterTolerationSeconds int32
+	// UnhealthyTolerationSeconds is how long the RayJob may be unhealthy before
+	// application failover moves it
+	UnhealthyTolerationSeconds int32
+	// GracePeriodSeconds is how long an evicted copy is kept while the job
+	// starts in another cluster
+	GracePeriodSeconds int32
+}
+
+// DefaultFailoverOptions returns the platform failover defaults
+func DefaultFailoverOptions() *FailoverOptions {
+	return &FailoverOptions{
+		ClusterTolerationSeconds:   DefaultClusterTolerationSeconds,
+		UnhealthyTolerationSeconds: DefaultUnhealthyTolerationSeconds,
+		GracePeriodSeconds:         DefaultFailoverGracePeriodSeconds,
+	}
+}
+
+// applyFailover configures cluster and application failover on a policy.
+// Karmada evicts workloads from clusters tainted NotReady or unreachable once
+// their toleration runs out, so the tolerations decide when a job leaves a
+// failed cluster; without a toleration time the job never leaves.
+func applyFailover(spec *policyv1alpha1.PropagationSpec, opts *FailoverOptions) {
+	if opts == nil {
+		return
+	}
+
+	var tolerationSeconds *int64
+	if !opts.Disabled {
+		seconds := int64(opts.ClusterTolerationSeconds)
+		tolerationSeconds = &seconds
+	}
+	for _, taint := range []string{clusterv1alpha1.TaintClusterNotReady, clusterv1alpha1.TaintClusterUnreachable} {
+		spec.Placement.ClusterTolerations = append(spec.Placement.ClusterTolerations, corev1.Toleration{
+			Key:               taint,
+			Operator:          corev1.TolerationOpExists,
+			Effect:            corev1.TaintEffectNoExecute,
+			TolerationSeconds: tolerationSeconds,
+		})
+	}
+
+	if opts.Disabled {
+		return
+	}
+
+	unhealthyTolerationSeconds := opts.UnhealthyTolerationSeconds
+	gracePeriodSeconds := opts.GracePeriodSeconds
+	spec.Failover = &policyv1alpha1.FailoverBehavior{
+		Application: &policyv1alpha1.ApplicationFailoverBehavior{
+			DecisionConditions: policyv1alpha1.DecisionConditions{
+				TolerationSeconds: &unhealthyTolerationSeconds,
+			},
+			// Keep the old copy until the new one is up or the grace period ends
+			PurgeMode:          policyv1alpha1.Graciously,
+			GracePeriodSeconds: &gracePeriodSeconds,
+		},
+	}
+}
@@ -0,0 +1,750 @@
+// Package fake provides an in-memory karmada.Interface for tests. It keeps
+// the objects created in the control plane, schedules RayJobs to fake member
+// clusters at once, and lets tests move the RayJob copies through their
+// lifecycle and inject failures. Watchers are told about every change
+// synchronously, before the changing call returns.
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+
+	"github.com/loiht2/ml-platform-training-job/backend/karmada"
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// RayJob deployment and job statuses the lifecycle steps through, as set by KubeRay
+const (
+	DeploymentInitializing = "Initializing"
+	DeploymentRunning      = "Running"
+	DeploymentComplete     = "Complete"
+	DeploymentFailed       = "Failed"
+
+	JobPending   = "PENDING"
+	JobRunning   = "RUNNING"
+	JobSucceeded = "SUCCEEDED"
+	JobFailed    = "FAILED"
+)
+
+// UnschedulableMessage is the scheduling message of a RayJob no member cluster was found for
+const UnschedulableMessage = "0/0 clusters are available: no cluster matches the placement"
+
+var (
+	clusterResource = schema.GroupResource{Group: "cluster.karmada.io", Resource: "clusters"}
+	rayJobResource  = schema.GroupResource{Group: "ray.io", Resource: "rayjobs"}
+	rayJobAPI       = schema.GroupVersion{Group: "ray.io", Version: "v1"}.String()
+	jobResource     = schema.GroupResource{Group: "batch", Resource: "jobs"}
+)
+
+// Client is an in-memory Karmada control plane with fake member clusters.
+// It is safe for concurrent use.
+type Client struct {
+	mu       sync.Mutex
+	clusters map[string]*memberCluster
+	// objects are the objects in the control plane
+	objects map[models.ResourceRef]bool
+	// placements are the clusters each RayJob was scheduled to, by namespace/name
+	placements   map[string][]string
+	propagations map[string]karmada.PropagationOptions
+	failures     map[string]error
+	// lingering are the objects reported as still being removed when deleted
+	lingering map[models.ResourceRef]bool
+	// events are the events of each job, by namespace/name of its RayJob
+	events map[string][]models.JobEvent
+	// claims are the PVC states set by tests, by cluster/namespace/name
+	claims map[string]karmada.ClaimStatus
+
+	// unwatchable makes Watch report that there is nothing to watch, as
+	// before the inventory synced
+	unwatchable bool
+	watchers    map[int]karmada.WatchHandler
+	nextWatcher int
+	// pending are the changes delivered to the watchers once mu is released
+	pending []func(karmada.WatchHandler)
+}
+
+// memberCluster is a fake member cluster holding the RayJob copies propagated to it
+type memberCluster struct {
+	info    models.ClusterInfo
+	rayJobs map[string]map[string]interface{}
+}
+
+var _ karmada.Interface = &Client{}
+
+// NewClient creates a fake Karmada control plane with the given member clusters
+func NewClient(clusters ...models.ClusterInfo) *Client {
+	c := &Client{
+		clusters:     map[string]*memberCluster{},
+		objects:      map[models.ResourceRef]bool{},
+		placements:   map[string][]string{},
+		propagations: map[string]karmada.PropagationOptions{},
+		failures:     map[string]error{},
+		lingering:    map[models.ResourceRef]bool{},
+		events:       map[string][]models.JobEvent{},
+		claims:       map[string]karmada.ClaimStatus{},
+		watchers:     map[int]karmada.WatchHandler{},
+	}
+	for _, cluster := range clusters {
+		c.AddCluster(cluster)
+	}
+	return c
+}
+
+// AddCluster registers a member cluster, replacing one with the same name
+func (c *Client) AddCluster(cluster models.ClusterInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clusters[cluster.Name] = &memberCluster{info: cluster, rayJobs: map[string]map[string]interface{}{}}
+}
+
+// SetClusterReady changes the readiness of a member cluster. RayJobs in a
+// cluster that is not ready cannot be read.
+func (c *Client) SetClusterReady(name string, ready bool) {
+	c.mu.Lock()
+	defer c.unlock()
+	if cluster, ok := c.clusters[name]; ok && cluster.info.Ready != ready {
+		cluster.info.Ready = ready
+		c.pending = append(c.pending, func(handler karmada.WatchHandler) {
+			if handler.ClusterReadinessChanged != nil {
+				handler.ClusterReadinessChanged(karmada.ClusterReadinessChange{Cluster: name, Ready: ready})
+			}
+		})
+	}
+}
+
+// FailOn makes every later call of the named Interface method fail with err,
+// or succeed again when err is nil. DeleteJobResources reports err for every
+// resource; ListResourcesInClusters reports it for every cluster.
+func (c *Client) FailOn(method string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		delete(c.failures, method)
+		return
+	}
+	c.failures[method] = err
+}
+
+// SetWatchable makes Watch fail, as for a client whose inventory has not
+// synced, or succeed again
+func (c *Client) SetWatchable(watchable bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.unwatchable = !watchable
+}
+
+// SetLingering makes DeleteJobResources report an object as still being
+// removed, as when its member cluster copies take a while to go, or lets the
+// next delete remove it when linger is false
+func (c *Client) SetLingering(ref models.ResourceRef, linger bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if linger {
+		c.lingering[ref] = true
+	} else {
+		delete(c.lingering, ref)
+	}
+}
+
+// SetClaimStatus sets the state GetClaimStatus reports for a PVC in a member cluster
+func (c *Client) SetClaimStatus(clusterName, namespace, name string, status karmada.ClaimStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.claims[clusterName+"/"+key(namespace, name)] = status
+}
+
+// Propagation returns the options a RayJob was created with
+func (c *Client) Propagation(namespace, name string) (karmada.PropagationOptions, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	opts, ok := c.propagations[key(namespace, name)]
+	return opts, ok
+}
+
+// Objects returns the objects in the control plane, sorted by kind and name
+func (c *Client) Objects() []models.ResourceRef {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	refs := make([]models.ResourceRef, 0, len(c.objects))
+	for ref := range c.objects {
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Kind != refs[j].Kind {
+			return refs[i].Kind < refs[j].Kind
+		}
+		return key(refs[i].Namespace, refs[i].Name) < key(refs[j].Namespace, refs[j].Name)
+	})
+	return refs
+}
+
+// RayJob returns a copy of a RayJob in a member cluster, or nil when the
+// cluster has none
+func (c *Client) RayJob(clusterName, namespace, name string) map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cluster, ok := c.clusters[clusterName]
+	if !ok {
+		return nil
+	}
+	rayJob, ok := cluster.rayJobs[key(namespace, name)]
+	if !ok {
+		return nil
+	}
+	return copyObject(rayJob)
+}
+
+// SetRayJobStatus sets the status of a RayJob copy in one member cluster
+func (c *Client) SetRayJobStatus(clusterName, namespace, name, deploymentStatus, jobStatus string) error {
+	c.mu.Lock()
+	defer c.unlock()
+	cluster, ok := c.clusters[clusterName]
+	if !ok {
+		return apierrors.NewNotFound(clusterResource, clusterName)
+	}
+	rayJob, ok := cluster.rayJobs[key(namespace, name)]
+	if !ok {
+		return apierrors.NewNotFound(rayJobResource, name)
+	}
+	setStatus(rayJob, deploymentStatus, jobStatus)
+	c.rayJobChanged(namespace, name)
+	return nil
+}
+
+// Step moves every copy of a RayJob to the next stage of its lifecycle:
+// Initializing, then Running, then Complete. Finished copies stay as they are.
+func (c *Client) Step(namespace, name string) error {
+	c.mu.Lock()
+	defer c.unlock()
+	clusters, ok := c.placements[key(namespace, name)]
+	if !ok {
+		return apierrors.NewNotFound(rayJobResource, name)
+	}
+	for _, clusterName := range clusters {
+		rayJob := c.clusters[clusterName].rayJobs[key(namespace, name)]
+		if rayJob == nil {
+			continue
+		}
+		status, _ := rayJob["status"].(map[string]interface{})
+		switch status["jobStatus"] {
+		case nil, "":
+			setStatus(rayJob, DeploymentInitializing, JobPending)
+		case JobPending:
+			setStatus(rayJob, DeploymentRunning, JobRunning)
+		case JobRunning:
+			setStatus(rayJob, DeploymentComplete, JobSucceeded)
+		}
+	}
+	c.rayJobChanged(namespace, name)
+	return nil
+}
+
+// AddEvent records an event of a job's RayJob. Events tagged with a member
+// cluster the RayJob is not placed on are not returned.
+func (c *Client) AddEvent(namespace, name string, event models.JobEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.events[key(namespace, name)] = append(c.events[key(namespace, name)], event)
+}
+
+// CreateRayJobWithPropagationPolicy creates the RayJob and its propagation
+// policy and schedules the RayJob right away: to the target clusters, else
+// the preferred cluster when it is ready, else every ready cluster with the
+// required labels
+func (c *Client) CreateRayJobWithPropagationPolicy(ctx context.Context, rayJob map[string]interface{}, opts karmada.PropagationOptions) ([]models.ResourceRef, error) {
+	c.mu.Lock()
+	defer c.unlock()
+	if err := c.failure("CreateRayJobWithPropagationPolicy"); err != nil {
+		return nil, err
+	}
+
+	obj := &unstructured.Unstructured{Object: rayJob}
+	namespace, name := obj.GetNamespace(), obj.GetName()
+	rayJobRef := models.ResourceRef{APIVersion: rayJobAPI, Kind: "RayJob", Namespace: namespace, Name: name}
+	if c.objects[rayJobRef] {
+		return nil, apierrors.NewAlreadyExists(rayJobResource, name)
+	}
+	policyRef := models.ResourceRef{
+		APIVersion: policyv1alpha1.SchemeGroupVersion.String(),
+		Kind:       "PropagationPolicy",
+		Namespace:  namespace,
+		Name:       fmt.Sprintf("%s-propagation", name),
+	}
+	c.objects[rayJobRef] = true
+	c.objects[policyRef] = true
+	c.propagations[key(namespace, name)] = opts
+
+	clusters := c.schedule(opts)
+	c.placements[key(namespace, name)] = clusters
+	for _, clusterName := range clusters {
+		member := copyObject(rayJob)
+		member["status"] = map[string]interface{}{}
+		c.clusters[clusterName].rayJobs[key(namespace, name)] = member
+	}
+	c.rayJobChanged(namespace, name)
+
+	return []models.ResourceRef{rayJobRef, policyRef}, nil
+}
+
+// schedule picks the member clusters of a RayJob
+func (c *Client) schedule(opts karmada.PropagationOptions) []string {
+	if len(opts.TargetClusters) > 0 {
+		var clusters []string
+		for _, name := range opts.TargetClusters {
+			if _, ok := c.clusters[name]; ok {
+				clusters = append(clusters, name)
+			}
+		}
+		return clusters
+	}
+	if preferred, ok := c.clusters[opts.PreferredCluster]; ok && preferred.info.Ready {
+		return []string{opts.PreferredCluster}
+	}
+
+	var required map[string]string
+	if opts.Placement != nil {
+		required = opts.Placement.ClusterLabels
+	}
+	var clusters []string
+	for _, name := range c.clusterNames() {
+		cluster := c.clusters[name].info
+		if cluster.Ready && labels.SelectorFromSet(required).Matches(labels.Set(cluster.Labels)) {
+			clusters = append(clusters, name)
+		}
+	}
+	return clusters
+}
+
+// CreatePVC creates a PVC in the control plane
+func (c *Client) CreatePVC(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (models.ResourceRef, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("CreatePVC"); err != nil {
+		return models.ResourceRef{}, err
+	}
+
+	ref := models.ResourceRef{APIVersion: "v1", Kind: "PersistentVolumeClaim", Namespace: pvc.Namespace, Name: pvc.Name}
+	if c.objects[ref] {
+		return models.ResourceRef{}, apierrors.NewAlreadyExists(corev1.Resource("persistentvolumeclaims"), pvc.Name)
+	}
+	c.objects[ref] = true
+	return ref, nil
+}
+
+// CreateConfigMap creates a ConfigMap in the control plane
+func (c *Client) CreateConfigMap(ctx context.Context, configMap *corev1.ConfigMap) (models.ResourceRef, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("CreateConfigMap"); err != nil {
+		return models.ResourceRef{}, err
+	}
+
+	ref := models.ResourceRef{APIVersion: "v1", Kind: "ConfigMap", Namespace: configMap.Namespace, Name: configMap.Name}
+	if c.objects[ref] {
+		return models.ResourceRef{}, apierrors.NewAlreadyExists(corev1.Resource("configmaps"), configMap.Name)
+	}
+	c.objects[ref] = true
+	return ref, nil
+}
+
+// CreateSecret creates a Secret in the control plane
+func (c *Client) CreateSecret(ctx context.Context, secret *corev1.Secret) (models.ResourceRef, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("CreateSecret"); err != nil {
+		return models.ResourceRef{}, err
+	}
+
+	ref := models.ResourceRef{APIVersion: "v1", Kind: "Secret", Namespace: secret.Namespace, Name: secret.Name}
+	if c.objects[ref] {
+		return models.ResourceRef{}, apierrors.NewAlreadyExists(corev1.Resource("secrets"), secret.Name)
+	}
+	c.objects[ref] = true
+	return ref, nil
+}
+
+// DeleteJobResources deletes objects from the control plane together with
+// their copies in the member clusters. Objects already gone count as deleted.
+func (c *Client) DeleteJobResources(ctx context.Context, resources []models.ResourceRef) []models.DeletionResult {
+	c.mu.Lock()
+	defer c.unlock()
+	err := c.failure("DeleteJobResources")
+
+	results := make([]models.DeletionResult, len(resources))
+	for i, ref := range resources {
+		results[i] = models.DeletionResult{ResourceRef: ref, Result: models.DeletionDeleted}
+		if err != nil {
+			results[i].Result = models.DeletionFailed
+			results[i].Error = err.Error()
+			continue
+		}
+		if c.lingering[ref] {
+			results[i].Result = models.DeletionPending
+			continue
+		}
+
+		delete(c.objects, ref)
+		if ref.Kind == "RayJob" {
+			for _, clusterName := range c.placements[key(ref.Namespace, ref.Name)] {
+				delete(c.clusters[clusterName].rayJobs, key(ref.Namespace, ref.Name))
+			}
+			delete(c.placements, key(ref.Namespace, ref.Name))
+			delete(c.propagations, key(ref.Namespace, ref.Name))
+			delete(c.events, key(ref.Namespace, ref.Name))
+			c.rayJobChanged(ref.Namespace, ref.Name)
+		}
+	}
+	return results
+}
+
+// EnsureRayJobSuspendRetention does nothing; the fake keeps no interpreter customizations
+func (c *Client) EnsureRayJobSuspendRetention(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.failure("EnsureRayJobSuspendRetention")
+}
+
+// GetJobStatus reports every Kubernetes Job as not found; the fake only runs RayJobs
+func (c *Client) GetJobStatus(ctx context.Context, name, namespace string) (*batchv1.Job, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("GetJobStatus"); err != nil {
+		return nil, err
+	}
+	return nil, apierrors.NewNotFound(jobResource, name)
+}
+
+// GetRayJobPlacement returns the clusters a RayJob was scheduled to, all applied and healthy
+func (c *Client) GetRayJobPlacement(ctx context.Context, name, namespace string) ([]models.ClusterPlacement, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("GetRayJobPlacement"); err != nil {
+		return nil, err
+	}
+
+	clusters, ok := c.placements[key(namespace, name)]
+	if !ok {
+		return nil, apierrors.NewNotFound(rayJobResource, name)
+	}
+	placement := make([]models.ClusterPlacement, 0, len(clusters))
+	for _, clusterName := range clusters {
+		placement = append(placement, models.ClusterPlacement{Cluster: clusterName, Applied: true, Health: "Healthy"})
+	}
+	return placement, nil
+}
+
+// GetRayJobsFromMembers reads the RayJob copies. Clusters that are not ready report an error.
+func (c *Client) GetRayJobsFromMembers(ctx context.Context, name, namespace string) ([]karmada.MemberRayJob, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("GetRayJobsFromMembers"); err != nil {
+		return nil, err
+	}
+
+	clusters, ok := c.placements[key(namespace, name)]
+	if !ok {
+		return nil, &karmada.NotScheduledError{Name: name}
+	}
+	if len(clusters) == 0 {
+		return nil, &karmada.NotScheduledError{Name: name, Message: UnschedulableMessage}
+	}
+
+	members := make([]karmada.MemberRayJob, 0, len(clusters))
+	for _, clusterName := range clusters {
+		cluster := c.clusters[clusterName]
+		member := karmada.MemberRayJob{Cluster: clusterName}
+		rayJob, ok := cluster.rayJobs[key(namespace, name)]
+		switch {
+		case !cluster.info.Ready:
+			member.Err = fmt.Errorf("cluster %s is not ready", clusterName)
+		case !ok:
+			member.Err = apierrors.NewNotFound(rayJobResource, name)
+		default:
+			member.RayJob = copyObject(rayJob)
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// GetVolcanoPodGroupPhase reports every PodGroup as Pending, like one not created yet
+func (c *Client) GetVolcanoPodGroupPhase(ctx context.Context, clusterName, namespace, name string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("GetVolcanoPodGroupPhase"); err != nil {
+		return "", err
+	}
+	return "Pending", nil
+}
+
+// GetClaimStatus reports the PVC state set by SetClaimStatus, and Bound for
+// any other claim
+func (c *Client) GetClaimStatus(ctx context.Context, clusterName, namespace, name string) (*karmada.ClaimStatus, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("GetClaimStatus"); err != nil {
+		return nil, err
+	}
+	if status, ok := c.claims[clusterName+"/"+key(namespace, name)]; ok {
+		return &status, nil
+	}
+	return &karmada.ClaimStatus{Phase: string(corev1.ClaimBound)}, nil
+}
+
+// GetJobEvents returns the recorded events of a job from Karmada and the
+// clusters the RayJob is placed on, oldest first. Clusters that are not ready
+// report an error.
+func (c *Client) GetJobEvents(ctx context.Context, name, namespace string) ([]models.JobEvent, map[string]error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	errs := map[string]error{}
+	if err := c.failure("GetJobEvents"); err != nil {
+		errs[models.KarmadaEventSource] = err
+		return nil, errs
+	}
+
+	sources := map[string]bool{models.KarmadaEventSource: true}
+	for _, clusterName := range c.placements[key(namespace, name)] {
+		if c.clusters[clusterName].info.Ready {
+			sources[clusterName] = true
+		} else {
+			errs[clusterName] = fmt.Errorf("cluster %s is not ready", clusterName)
+		}
+	}
+
+	var events []models.JobEvent
+	for _, event := range c.events[key(namespace, name)] {
+		if sources[event.Cluster] {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+	return events, errs
+}
+
+// ListMemberClusters lists the member clusters by name
+func (c *Client) ListMemberClusters(ctx context.Context) ([]models.ClusterInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("ListMemberClusters"); err != nil {
+		return nil, err
+	}
+	return c.clusterInfos(), nil
+}
+
+// ListMemberClusterCapacity lists the member clusters by name
+func (c *Client) ListMemberClusterCapacity(ctx context.Context) ([]models.ClusterInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("ListMemberClusterCapacity"); err != nil {
+		return nil, err
+	}
+	return c.clusterInfos(), nil
+}
+
+// GetClusterCapacity returns a member cluster
+func (c *Client) GetClusterCapacity(ctx context.Context, clusterName string) (*models.ClusterInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("GetClusterCapacity"); err != nil {
+		return nil, err
+	}
+	cluster, ok := c.clusters[clusterName]
+	if !ok {
+		return nil, apierrors.NewNotFound(clusterResource, clusterName)
+	}
+	info := cluster.info
+	return &info, nil
+}
+
+// ListClusterResources lists the RayJobs of a member cluster matching the
+// query's namespace and label selector. Other resources are always empty.
+func (c *Client) ListClusterResources(ctx context.Context, clusterName string, query karmada.ResourceQuery) (*models.ClusterResources, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("ListClusterResources"); err != nil {
+		return nil, err
+	}
+	return c.listClusterResources(clusterName, query)
+}
+
+// ListResourcesInClusters lists resources in several member clusters, reporting failures per cluster
+func (c *Client) ListResourcesInClusters(ctx context.Context, clusters []string, query karmada.ResourceQuery) []models.ClusterResources {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	failure := c.failure("ListResourcesInClusters")
+
+	results := make([]models.ClusterResources, len(clusters))
+	for i, clusterName := range clusters {
+		results[i] = models.ClusterResources{Cluster: clusterName, Resources: []map[string]interface{}{}}
+		if failure != nil {
+			results[i].Error = failure.Error()
+			continue
+		}
+		list, err := c.listClusterResources(clusterName, query)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		results[i] = *list
+	}
+	return results
+}
+
+func (c *Client) listClusterResources(clusterName string, query karmada.ResourceQuery) (*models.ClusterResources, error) {
+	cluster, ok := c.clusters[clusterName]
+	if !ok {
+		return nil, apierrors.NewNotFound(clusterResource, clusterName)
+	}
+	selector, err := labels.Parse(query.LabelSelector)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+
+	result := &models.ClusterResources{Cluster: clusterName, Resources: []map[string]interface{}{}}
+	if query.Resource != rayJobResource.Resource && query.Resource != rayJobResource.String() {
+		return result, nil
+	}
+	result.Kind = "RayJob"
+
+	keys := make([]string, 0, len(cluster.rayJobs))
+	for k := range cluster.rayJobs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		obj := &unstructured.Unstructured{Object: cluster.rayJobs[k]}
+		if query.Namespace != "" && obj.GetNamespace() != query.Namespace {
+			continue
+		}
+		if !selector.Matches(labels.Set(obj.GetLabels())) {
+			continue
+		}
+		result.Resources = append(result.Resources, copyObject(obj.Object))
+	}
+	result.Count = len(result.Resources)
+	return result, nil
+}
+
+// failure returns the error injected for a method; callers hold c.mu
+func (c *Client) failure(method string) error {
+	return c.failures[method]
+}
+
+func (c *Client) clusterNames() []string {
+	names := make([]string, 0, len(c.clusters))
+	for name := range c.clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c *Client) clusterInfos() []models.ClusterInfo {
+	infos := make([]models.ClusterInfo, 0, len(c.clusters))
+	for _, name := range c.clusterNames() {
+		infos = append(infos, c.clusters[name].info)
+	}
+	return infos
+}
+
+func key(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// setStatus sets a RayJob's status fields, with the start and end times KubeRay records
+func setStatus(rayJob map[string]interface{}, deploymentStatus, jobStatus string) {
+	status, ok := rayJob["status"].(map[string]interface{})
+	if !ok {
+		status = map[string]interface{}{}
+		rayJob["status"] = status
+	}
+	status["jobDeploymentStatus"] = deploymentStatus
+	status["jobStatus"] = jobStatus
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	switch jobStatus {
+	case JobRunning:
+		if _, ok := status["startTime"]; !ok {
+			status["startTime"] = now
+		}
+	case JobSucceeded, JobFailed:
+		if _, ok := status["startTime"]; !ok {
+			status["startTime"] = now
+		}
+		status["endTime"] = now
+	}
+}
+
+// copyObject deep-copies an object the way the API server returns it,
+// through JSON, so typed slices and maps become generic ones
+func copyObject(obj map[string]interface{}) map[string]interface{} {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		panic(fmt.Sprintf("fake: failed to copy object: %v", err))
+	}
+	var copied map[string]interface{}
+	if err := json.Unmarshal(data, &copied); err != nil {
+		panic(fmt.Sprintf("fake: failed to copy object: %v", err))
+	}
+	return copied
+}
+
+// Watch reports every RayJob and cluster readiness change to the handler
+// until stop is called, unless the client was made unwatchable
+func (c *Client) Watch(handler karmada.WatchHandler) (func(), bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.unwatchable {
+		return nil, false
+	}
+	id := c.nextWatcher
+	c.nextWatcher++
+	c.watchers[id] = handler
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.watchers, id)
+	}, true
+}
+
+// rayJobChanged queues a RayJob change for the watchers; c.mu must be held
+func (c *Client) rayJobChanged(namespace, name string) {
+	c.pending = append(c.pending, func(handler karmada.WatchHandler) {
+		if handler.RayJobChanged != nil {
+			handler.RayJobChanged(namespace, name)
+		}
+	})
+}
+
+// unlock releases c.mu and then delivers the pending changes, so watchers may call the client
+func (c *Client) unlock() {
+	pending := c.pending
+	c.pending = nil
+	watchers := make([]karmada.WatchHandler, 0, len(c.watchers))
+	for _, handler := range c.watchers {
+		watchers = append(watchers, handler)
+	}
+	c.mu.Unlock()
+
+	for _, change := range pending {
+		for _, handler := range watchers {
+			change(handler)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package karmada
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Federations routes Karmada operations to the control plane of each
+// federation. An empty federation name means the default federation.
+type Federations struct {
+	defaultName string
+	clients     map[string]Interface
+}
+
+// UnknownFederationError is returned for a federation that is not configured
+type UnknownFederationError struct {
+	Name string
+}
+
+func (e *UnknownFederationError) Error() string {
+	return fmt.Sprintf("unknown federation %q", e.Name)
+}
+
+// NewFederations creates the federation routing. clients must include the default federation.
+func NewFederations(defaultName string, clients map[string]Interface) *Federations {
+	return &Federations{defaultName: defaultName, clients: clients}
+}
+
+// Get returns the client of a federation
+func (f *Federations) Get(name string) (Interface, error) {
+	if name == "" {
+		name = f.defaultName
+	}
+	client, ok := f.clients[name]
+	if !ok {
+		return nil, &UnknownFederationError{Name: name}
+	}
+	return client, nil
+}
+
+// Default returns the name of the default federation
+func (f *Federations) Default() string {
+	return f.defaultName
+}
+
+// Names returns the federation names, the default first and the others sorted
+func (f *Federations) Names() []string {
+	names := make([]string, 0, len(f.clients))
+	for name := range f.clients {
+		if name != f.defaultName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{f.defaultName}, names...)
+}
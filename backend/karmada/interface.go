@@ -0,0 +1,43 @@
+package karmada
+
+import (
+	"context"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// Interface is the set of Karmada operations the handlers and the job
+// monitor use. Client implements it against a Karmada control plane; the
+// fake package implements it in memory for tests.
+type Interface interface {
+	// Jobs
+	CreateRayJobWithPropagationPolicy(ctx context.Context, rayJob map[string]interface{}, opts PropagationOptions) ([]models.ResourceRef, error)
+	CreatePVC(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (models.ResourceRef, error)
+	CreateConfigMap(ctx context.Context, configMap *corev1.ConfigMap) (models.ResourceRef, error)
+	CreateSecret(ctx context.Context, secret *corev1.Secret) (models.ResourceRef, error)
+	DeleteJobResources(ctx context.Context, resources []models.ResourceRef) []models.DeletionResult
+	EnsureRayJobSuspendRetention(ctx context.Context) error
+
+	// Job status
+	GetJobStatus(ctx context.Context, name, namespace string) (*batchv1.Job, error)
+	GetRayJobPlacement(ctx context.Context, name, namespace string) ([]models.ClusterPlacement, error)
+	GetRayJobsFromMembers(ctx context.Context, name, namespace string) ([]MemberRayJob, error)
+	GetVolcanoPodGroupPhase(ctx context.Context, clusterName, namespace, name string) (string, error)
+	GetClaimStatus(ctx context.Context, clusterName, namespace, name string) (*ClaimStatus, error)
+	GetJobEvents(ctx context.Context, name, namespace string) ([]models.JobEvent, map[string]error)
+
+	// Member clusters
+	ListMemberClusters(ctx context.Context) ([]models.ClusterInfo, error)
+	ListMemberClusterCapacity(ctx context.Context) ([]models.ClusterInfo, error)
+	GetClusterCapacity(ctx context.Context, clusterName string) (*models.ClusterInfo, error)
+	ListClusterResources(ctx context.Context, clusterName string, query ResourceQuery) (*models.ClusterResources, error)
+	ListResourcesInClusters(ctx context.Context, clusters []string, query ResourceQuery) []models.ClusterResources
+
+	// Changes
+	Watch(handler WatchHandler) (stop func(), ok bool)
+}
+
+var _ Interface = &Client{}
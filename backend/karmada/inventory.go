@@ -0,0 +1,239 @@
+package karmada
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	workv1alpha2 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha2"
+	karmadaclientset "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	informers "github.com/karmada-io/karmada/pkg/generated/informers/externalversions"
+	clusterlisters "github.com/karmada-io/karmada/pkg/generated/listers/cluster/v1alpha1"
+	worklisters "github.com/karmada-io/karmada/pkg/generated/listers/work/v1alpha2"
+)
+
+// inventoryResync is how often the informers replay their cache to the event handlers
+const inventoryResync = 10 * time.Minute
+
+// ClusterReadinessChange reports a member cluster that became ready or not ready
+type ClusterReadinessChange struct {
+	Cluster string
+	Ready   bool
+}
+
+// Inventory caches the Clusters and ResourceBindings of a Karmada control
+// plane through shared informers, so that listing clusters and reading job
+// bindings does not go to the API server each time. Objects read from it are
+// shared with the cache and must not be modified.
+type Inventory struct {
+	factory  informers.SharedInformerFactory
+	clusters clusterlisters.ClusterLister
+	bindings worklisters.ResourceBindingLister
+	synced   []cache.InformerSynced
+	// bindingInformer feeds the binding changes of Watch
+	bindingInformer cache.SharedIndexInformer
+	// stop ends the informers started by Start
+	stop context.CancelFunc
+
+	mu          sync.Mutex
+	subscribers map[chan ClusterReadinessChange]bool
+}
+
+// NewInventory creates the informers of the inventory; Start runs them
+func NewInventory(karmadaClient karmadaclientset.Interface) *Inventory {
+	factory := informers.NewSharedInformerFactory(karmadaClient, inventoryResync)
+	clusterInformer := factory.Cluster().V1alpha1().Clusters()
+	bindingInformer := factory.Work().V1alpha2().ResourceBindings()
+
+	inventory := &Inventory{
+		factory:     factory,
+		clusters:    clusterInformer.Lister(),
+		bindings:    bindingInformer.Lister(),
+		synced:      []cache.InformerSynced{clusterInformer.Informer().HasSynced, bindingInformer.Informer().HasSynced},
+		subscribers: map[chan ClusterReadinessChange]bool{},
+
+		bindingInformer: bindingInformer.Informer(),
+	}
+	clusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: inventory.clusterUpdated,
+		DeleteFunc: inventory.clusterDeleted,
+	})
+	return inventory
+}
+
+// Start runs the informers until ctx is done and waits up to syncTimeout for
+// their caches to fill. The informers are stopped if they do not sync in time.
+func (i *Inventory) Start(ctx context.Context, syncTimeout time.Duration) error {
+	var runCtx context.Context
+	runCtx, i.stop = context.WithCancel(ctx)
+	i.factory.Start(runCtx.Done())
+
+	syncCtx, cancel := context.WithTimeout(runCtx, syncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(syncCtx.Done(), i.synced...) {
+		i.stop()
+		return fmt.Errorf("failed to sync the Karmada inventory cache within %s", syncTimeout)
+	}
+	return nil
+}
+
+// Clusters returns the member clusters, sorted by name
+func (i *Inventory) Clusters() ([]*clusterv1alpha1.Cluster, error) {
+	clusters, err := i.clusters.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(clusters, func(a, b int) bool {
+		return clusters[a].Name < clusters[b].Name
+	})
+	return clusters, nil
+}
+
+// Cluster returns a member cluster, or a NotFound error
+func (i *Inventory) Cluster(name string) (*clusterv1alpha1.Cluster, error) {
+	return i.clusters.Get(name)
+}
+
+// ResourceBinding returns a ResourceBinding, or a NotFound error
+func (i *Inventory) ResourceBinding(namespace, name string) (*workv1alpha2.ResourceBinding, error) {
+	return i.bindings.ResourceBindings(namespace).Get(name)
+}
+
+// Subscribe returns a channel receiving every change of a member cluster's
+// readiness, and a function to stop the subscription. A subscriber that falls
+// behind misses changes rather than holding up the others.
+func (i *Inventory) Subscribe() (<-chan ClusterReadinessChange, func()) {
+	ch := make(chan ClusterReadinessChange, 16)
+	i.mu.Lock()
+	i.subscribers[ch] = true
+	i.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			i.mu.Lock()
+			delete(i.subscribers, ch)
+			i.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+func (i *Inventory) clusterUpdated(oldObj, newObj interface{}) {
+	oldCluster, ok := oldObj.(*clusterv1alpha1.Cluster)
+	if !ok {
+		return
+	}
+	newCluster, ok := newObj.(*clusterv1alpha1.Cluster)
+	if !ok {
+		return
+	}
+	if ready := clusterReady(newCluster); ready != clusterReady(oldCluster) {
+		i.notify(ClusterReadinessChange{Cluster: newCluster.Name, Ready: ready})
+	}
+}
+
+func (i *Inventory) clusterDeleted(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if cluster, ok := obj.(*clusterv1alpha1.Cluster); ok && clusterReady(cluster) {
+		i.notify(ClusterReadinessChange{Cluster: cluster.Name, Ready: false})
+	}
+}
+
+func (i *Inventory) notify(change ClusterReadinessChange) {
+	log.Printf("Member cluster %s readiness changed: ready=%t", change.Cluster, change.Ready)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for ch := range i.subscribers {
+		select {
+		case ch <- change:
+		default:
+		}
+	}
+}
+
+// StartInventory runs the informer cache of the client's control plane until
+// ctx is done and reads Clusters and ResourceBindings from it from then on.
+// It may be called while the client is in use, and again after it failed.
+func (c *Client) StartInventory(ctx context.Context, syncTimeout time.Duration) error {
+	inventory := NewInventory(c.karmadaClient)
+	if err := inventory.Start(ctx, syncTimeout); err != nil {
+		return err
+	}
+	c.inventory.Store(inventory)
+	return nil
+}
+
+// Inventory returns the informer cache of the client's control plane, or nil
+// until StartInventory succeeded
+func (c *Client) Inventory() *Inventory {
+	return c.inventory.Load()
+}
+
+// listClusters lists the member clusters from the cache, or from Karmada
+// without one, sorted by name
+func (c *Client) listClusters(ctx context.Context) ([]*clusterv1alpha1.Cluster, error) {
+	if inventory := c.Inventory(); inventory != nil {
+		return inventory.Clusters()
+	}
+	clusterList, err := c.karmadaClient.ClusterV1alpha1().Clusters().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list member clusters: %w", err)
+	}
+	clusters := make([]*clusterv1alpha1.Cluster, len(clusterList.Items))
+	for i := range clusterList.Items {
+		clusters[i] = &clusterList.Items[i]
+	}
+	return clusters, nil
+}
+
+// getCluster gets a member cluster from the cache, or from Karmada without one
+func (c *Client) getCluster(ctx context.Context, name string) (*clusterv1alpha1.Cluster, error) {
+	var cluster *clusterv1alpha1.Cluster
+	var err error
+	if inventory := c.Inventory(); inventory != nil {
+		cluster, err = inventory.Cluster(name)
+	} else {
+		cluster, err = c.karmadaClient.ClusterV1alpha1().Clusters().Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member cluster %s: %w", name, err)
+	}
+	return cluster, nil
+}
+
+// getResourceBinding gets a ResourceBinding from the cache, or from Karmada without one
+func (c *Client) getResourceBinding(ctx context.Context, namespace, name string) (*workv1alpha2.ResourceBinding, error) {
+	var binding *workv1alpha2.ResourceBinding
+	var err error
+	if inventory := c.Inventory(); inventory != nil {
+		binding, err = inventory.ResourceBinding(namespace, name)
+	} else {
+		binding, err = c.karmadaClient.WorkV1alpha2().ResourceBindings(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource binding %s/%s: %w", namespace, name, err)
+	}
+	return binding, nil
+}
+
+// clusterReady reports whether a cluster's Ready condition is true
+func clusterReady(cluster *clusterv1alpha1.Cluster) bool {
+	for _, condition := range cluster.Status.Conditions {
+		if condition.Type == clusterv1alpha1.ClusterConditionReady {
+			return condition.Status == metav1.ConditionTrue
+		}
+	}
+	return false
+}
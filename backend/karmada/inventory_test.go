@@ -0,0 +1,145 @@
+package karmada
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	workv1alpha2 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha2"
+	karmadafake "github.com/karmada-io/karmada/pkg/generated/clientset/versioned/fake"
+)
+
+func readyCluster(name string, ready bool) *clusterv1alpha1.Cluster {
+	status := metav1.ConditionFalse
+	if ready {
+		status = metav1.ConditionTrue
+	}
+	return &clusterv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: clusterv1alpha1.ClusterStatus{
+			Conditions: []metav1.Condition{{Type: clusterv1alpha1.ClusterConditionReady, Status: status}},
+		},
+	}
+}
+
+func TestInventory(t *testing.T) {
+	karmadaClient := karmadafake.NewSimpleClientset(
+		readyCluster("member2", true),
+		readyCluster("member1", true),
+		&workv1alpha2.ResourceBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "ml", Name: "job-rayjob"}},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	inventory := NewInventory(karmadaClient)
+	if err := inventory.Start(ctx, 10*time.Second); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	changes, unsubscribe := inventory.Subscribe()
+	defer unsubscribe()
+
+	clusters, err := inventory.Clusters()
+	if err != nil {
+		t.Fatalf("Clusters: %v", err)
+	}
+	if len(clusters) != 2 || clusters[0].Name != "member1" || clusters[1].Name != "member2" {
+		t.Fatalf("Clusters = %v, want member1 and member2", clusters)
+	}
+	if _, err := inventory.ResourceBinding("ml", "job-rayjob"); err != nil {
+		t.Errorf("ResourceBinding: %v", err)
+	}
+	if _, err := inventory.Cluster("member3"); !apierrors.IsNotFound(err) {
+		t.Errorf("Cluster(member3) error = %v, want NotFound", err)
+	}
+
+	_, err = karmadaClient.ClusterV1alpha1().Clusters().Update(ctx, readyCluster("member1", false), metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("update cluster: %v", err)
+	}
+	select {
+	case change := <-changes:
+		if change != (ClusterReadinessChange{Cluster: "member1", Ready: false}) {
+			t.Errorf("change = %+v, want member1 not ready", change)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no readiness change after member1 became NotReady")
+	}
+
+	if err := karmadaClient.ClusterV1alpha1().Clusters().Delete(ctx, "member2", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("delete cluster: %v", err)
+	}
+	select {
+	case change := <-changes:
+		if change != (ClusterReadinessChange{Cluster: "member2", Ready: false}) {
+			t.Errorf("change = %+v, want member2 not ready", change)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no readiness change after member2 was deleted")
+	}
+}
+
+func TestInventoryWatch(t *testing.T) {
+	karmadaClient := karmadafake.NewSimpleClientset(
+		readyCluster("member1", true),
+		&workv1alpha2.ResourceBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ml", Name: "job-rayjob"},
+			Spec: workv1alpha2.ResourceBindingSpec{
+				Resource: workv1alpha2.ObjectReference{APIVersion: "ray.io/v1", Kind: "RayJob", Namespace: "ml", Name: "job"},
+			},
+		},
+		&workv1alpha2.ResourceBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ml", Name: "job-pvc-persistentvolumeclaim"},
+			Spec: workv1alpha2.ResourceBindingSpec{
+				Resource: workv1alpha2.ObjectReference{APIVersion: "v1", Kind: "PersistentVolumeClaim", Namespace: "ml", Name: "job-pvc"},
+			},
+		},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	inventory := NewInventory(karmadaClient)
+	if err := inventory.Start(ctx, 10*time.Second); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	rayJobs := make(chan string, 10)
+	readiness := make(chan ClusterReadinessChange, 10)
+	stop := inventory.Watch(WatchHandler{
+		RayJobChanged:           func(namespace, name string) { rayJobs <- namespace + "/" + name },
+		ClusterReadinessChanged: func(change ClusterReadinessChange) { readiness <- change },
+	})
+	defer stop()
+
+	// Existing bindings are reported as added; bindings of other kinds are not
+	select {
+	case rayJob := <-rayJobs:
+		if rayJob != "ml/job" {
+			t.Errorf("changed RayJob = %s, want ml/job", rayJob)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("existing RayJob binding was not reported")
+	}
+
+	_, err := karmadaClient.ClusterV1alpha1().Clusters().Update(ctx, readyCluster("member1", false), metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("update cluster: %v", err)
+	}
+	select {
+	case change := <-readiness:
+		if change != (ClusterReadinessChange{Cluster: "member1", Ready: false}) {
+			t.Errorf("change = %+v, want member1 not ready", change)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no readiness change after member1 became NotReady")
+	}
+
+	select {
+	case rayJob := <-rayJobs:
+		t.Errorf("unexpected change of RayJob %s", rayJob)
+	default:
+	}
+}
@@ -0,0 +1,299 @@
+package karmada
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// overridePolicyName is the OverridePolicy adapting a job's RayJob to each cluster
+func overridePolicyName(name string) string {
+	return fmt.Sprintf("%s-override", name)
+}
+
+// pvcOverridePolicyName is the OverridePolicy adapting a job's PVC to each cluster.
+// Overriders apply to every selected resource, so the PVC needs its own policy.
+func pvcOverridePolicyName(name string) string {
+	return fmt.Sprintf("%s-pvc-override", name)
+}
+
+// createOverridePolicies creates the OverridePolicies for a RayJob and the PVCs
+// among its dependencies, returning the policies it created
+func (c *Client) createOverridePolicies(ctx context.Context, rayJob *unstructured.Unstructured, opts PropagationOptions) ([]models.ResourceRef, error) {
+	if len(opts.Overrides) == 0 {
+		return nil, nil
+	}
+
+	policies := []*policyv1alpha1.OverridePolicy{}
+	policy, err := buildRayJobOverridePolicy(rayJob, opts.Overrides)
+	if err != nil {
+		return nil, err
+	}
+	if policy != nil {
+		policies = append(policies, policy)
+	}
+
+	for _, dependency := range opts.Dependencies {
+		if dependency.Kind != "PersistentVolumeClaim" {
+			continue
+		}
+		policy, err := buildPVCOverridePolicy(rayJob.GetName(), rayJob.GetNamespace(), dependency.Name, opts.Overrides)
+		if err != nil {
+			return nil, err
+		}
+		if policy != nil {
+			policies = append(policies, policy)
+		}
+	}
+
+	var created []models.ResourceRef
+	for _, policy := range policies {
+		if _, err := c.karmadaClient.PolicyV1alpha1().OverridePolicies(policy.Namespace).Create(ctx, policy, metav1.CreateOptions{}); err != nil {
+			return created, fmt.Errorf("failed to create override policy %s: %w", policy.Name, err)
+		}
+		created = append(created, resourceRef(&policy.TypeMeta, &policy.ObjectMeta))
+		log.Printf("Created override policy %s/%s", policy.Namespace, policy.Name)
+	}
+	return created, nil
+}
+
+// buildRayJobOverridePolicy builds one override rule per cluster for the pod
+// templates of the RayJob's head and worker groups. Karmada cannot locate
+// images in custom resources by itself, so every image path is spelled out.
+func buildRayJobOverridePolicy(rayJob *unstructured.Unstructured, overrides map[string]models.ClusterOverrides) (*policyv1alpha1.OverridePolicy, error) {
+	templates := rayJobPodTemplates(rayJob)
+
+	rules := []policyv1alpha1.RuleWithCluster{}
+	for _, cluster := range sortedKeys(overrides) {
+		o := overrides[cluster]
+		overriders := policyv1alpha1.Overriders{}
+
+		for _, template := range templates {
+			if o.ImageRegistry != "" {
+				for _, imagePath := range template.imagePaths() {
+					overriders.ImageOverrider = append(overriders.ImageOverrider, policyv1alpha1.ImageOverrider{
+						Predicate: &policyv1alpha1.ImagePredicate{Path: imagePath},
+						Component: policyv1alpha1.Registry,
+						Operator:  policyv1alpha1.OverriderOpReplace,
+						Value:     o.ImageRegistry,
+					})
+				}
+			}
+
+			plaintext, err := template.nodeSelectorPatches(o.NodeSelector)
+			if err != nil {
+				return nil, err
+			}
+			overriders.Plaintext = append(overriders.Plaintext, plaintext...)
+
+			plaintext, err = template.envPatches(o.Env)
+			if err != nil {
+				return nil, err
+			}
+			overriders.Plaintext = append(overriders.Plaintext, plaintext...)
+		}
+
+		if len(overriders.ImageOverrider) > 0 || len(overriders.Plaintext) > 0 {
+			rules = append(rules, clusterRule(cluster, overriders))
+		}
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	return newOverridePolicy(overridePolicyName(rayJob.GetName()), rayJob.GetNamespace(), policyv1alpha1.ResourceSelector{
+		APIVersion: rayJob.GetAPIVersion(),
+		Kind:       rayJob.GetKind(),
+		Name:       rayJob.GetName(),
+	}, rules), nil
+}
+
+// buildPVCOverridePolicy sets the storage class of a job's PVC per cluster
+func buildPVCOverridePolicy(jobName, namespace, pvcName string, overrides map[string]models.ClusterOverrides) (*policyv1alpha1.OverridePolicy, error) {
+	rules := []policyv1alpha1.RuleWithCluster{}
+	for _, cluster := range sortedKeys(overrides) {
+		storageClassName := overrides[cluster].StorageClassName
+		if storageClassName == "" {
+			continue
+		}
+		patch, err := plaintextAdd("/spec/storageClassName", storageClassName)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, clusterRule(cluster, policyv1alpha1.Overriders{
+			Plaintext: []policyv1alpha1.PlaintextOverrider{patch},
+		}))
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	return newOverridePolicy(pvcOverridePolicyName(jobName), namespace, policyv1alpha1.ResourceSelector{
+		APIVersion: "v1",
+		Kind:       "PersistentVolumeClaim",
+		Name:       pvcName,
+	}, rules), nil
+}
+
+func newOverridePolicy(name, namespace string, selector policyv1alpha1.ResourceSelector, rules []policyv1alpha1.RuleWithCluster) *policyv1alpha1.OverridePolicy {
+	return &policyv1alpha1.OverridePolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "policy.karmada.io/v1alpha1",
+			Kind:       "OverridePolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: policyv1alpha1.OverrideSpec{
+			ResourceSelectors: []policyv1alpha1.ResourceSelector{selector},
+			OverrideRules:     rules,
+		},
+	}
+}
+
+func clusterRule(cluster string, overriders policyv1alpha1.Overriders) policyv1alpha1.RuleWithCluster {
+	return policyv1alpha1.RuleWithCluster{
+		TargetCluster: &policyv1alpha1.ClusterAffinity{ClusterNames: []string{cluster}},
+		Overriders:    overriders,
+	}
+}
+
+// podTemplate is a pod template inside the RayJob, addressed by its JSON pointer
+type podTemplate struct {
+	path string
+	spec map[string]interface{}
+}
+
+// rayJobPodTemplates returns the head and worker pod templates of a RayJob.
+// The RayJob is built by the converter and holds non-JSON values such as
+// []string, so the fields are read without the deep copy NestedMap makes.
+func rayJobPodTemplates(rayJob *unstructured.Unstructured) []podTemplate {
+	var templates []podTemplate
+	if head, found, _ := unstructured.NestedFieldNoCopy(rayJob.Object, "spec", "rayClusterSpec", "headGroupSpec", "template", "spec"); found {
+		if spec, ok := head.(map[string]interface{}); ok {
+			templates = append(templates, podTemplate{path: "/spec/rayClusterSpec/headGroupSpec/template", spec: spec})
+		}
+	}
+	workers, _, _ := unstructured.NestedFieldNoCopy(rayJob.Object, "spec", "rayClusterSpec", "workerGroupSpecs")
+	workerList, _ := workers.([]interface{})
+	for i, w := range workerList {
+		worker, _ := w.(map[string]interface{})
+		if spec, found, _ := unstructured.NestedFieldNoCopy(worker, "template", "spec"); found {
+			if spec, ok := spec.(map[string]interface{}); ok {
+				templates = append(templates, podTemplate{path: fmt.Sprintf("/spec/rayClusterSpec/workerGroupSpecs/%d/template", i), spec: spec})
+			}
+		}
+	}
+	return templates
+}
+
+// imagePaths returns the paths of all container and init container images
+func (t podTemplate) imagePaths() []string {
+	var paths []string
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _ := t.spec[field].([]interface{})
+		for i := range containers {
+			paths = append(paths, fmt.Sprintf("%s/spec/%s/%d/image", t.path, field, i))
+		}
+	}
+	return paths
+}
+
+// nodeSelectorPatches adds node selector entries, keeping any the template already has
+func (t podTemplate) nodeSelectorPatches(nodeSelector map[string]string) ([]policyv1alpha1.PlaintextOverrider, error) {
+	if len(nodeSelector) == 0 {
+		return nil, nil
+	}
+	if t.spec["nodeSelector"] == nil {
+		patch, err := plaintextAdd(t.path+"/spec/nodeSelector", nodeSelector)
+		if err != nil {
+			return nil, err
+		}
+		return []policyv1alpha1.PlaintextOverrider{patch}, nil
+	}
+
+	patches := []policyv1alpha1.PlaintextOverrider{}
+	for _, key := range sortedKeys(nodeSelector) {
+		patch, err := plaintextAdd(t.path+"/spec/nodeSelector/"+escapeJSONPointer(key), nodeSelector[key])
+		if err != nil {
+			return nil, err
+		}
+		patches = append(patches, patch)
+	}
+	return patches, nil
+}
+
+// envPatches appends env vars to the main containers
+func (t podTemplate) envPatches(env map[string]string) ([]policyv1alpha1.PlaintextOverrider, error) {
+	if len(env) == 0 {
+		return nil, nil
+	}
+	entries := make([]corev1.EnvVar, 0, len(env))
+	for _, name := range sortedKeys(env) {
+		entries = append(entries, corev1.EnvVar{Name: name, Value: env[name]})
+	}
+
+	patches := []policyv1alpha1.PlaintextOverrider{}
+	containers, _ := t.spec["containers"].([]interface{})
+	for i, c := range containers {
+		container, _ := c.(map[string]interface{})
+		envPath := fmt.Sprintf("%s/spec/containers/%d/env", t.path, i)
+
+		if container["env"] == nil {
+			patch, err := plaintextAdd(envPath, entries)
+			if err != nil {
+				return nil, err
+			}
+			patches = append(patches, patch)
+			continue
+		}
+		for _, entry := range entries {
+			patch, err := plaintextAdd(envPath+"/-", entry)
+			if err != nil {
+				return nil, err
+			}
+			patches = append(patches, patch)
+		}
+	}
+	return patches, nil
+}
+
+func plaintextAdd(path string, value interface{}) (policyv1alpha1.PlaintextOverrider, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return policyv1alpha1.PlaintextOverrider{}, fmt.Errorf("failed to marshal override for %s: %w", path, err)
+	}
+	return policyv1alpha1.PlaintextOverrider{
+		Path:     path,
+		Operator: policyv1alpha1.OverriderOpAdd,
+		Value:    apiextensionsv1.JSON{Raw: raw},
+	}, nil
+}
+
+// escapeJSONPointer escapes a map key for use in a JSON pointer (RFC 6901)
+func escapeJSONPointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
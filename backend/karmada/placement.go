@@ -0,0 +1,166 @@
+package karmada
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	workv1alpha2 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha2"
+	"github.com/karmada-io/karmada/pkg/util/names"
+
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// rayJobKind is the kind Karmada names RayJob bindings after
+const rayJobKind = "RayJob"
+
+// GetRayJobBinding gets the ResourceBinding Karmada created for a RayJob
+func (c *Client) GetRayJobBinding(ctx context.Context, name, namespace string) (*workv1alpha2.ResourceBinding, error) {
+	return c.getResourceBinding(ctx, namespace, names.GenerateBindingName(rayJobKind, name))
+}
+
+// NotScheduledError reports a RayJob Karmada has not scheduled to any member
+// cluster yet: its ResourceBinding does not exist yet or targets no cluster
+type NotScheduledError struct {
+	Name string
+	// Message is the message of the binding's Scheduled condition, if any
+	Message string
+}
+
+func (e *NotScheduledError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("job %s has not been scheduled to any cluster yet", e.Name)
+	}
+	return fmt.Sprintf("job %s has not been scheduled to any cluster yet: %s", e.Name, e.Message)
+}
+
+// schedulingMessage returns the message of a binding's Scheduled condition
+func schedulingMessage(binding *workv1alpha2.ResourceBinding) string {
+	if condition := meta.FindStatusCondition(binding.Status.Conditions, workv1alpha2.Scheduled); condition != nil {
+		return condition.Message
+	}
+	return ""
+}
+
+// GetRayJobPlacement returns the member clusters Karmada scheduled a RayJob to.
+// It is empty while the RayJob has not been scheduled yet.
+func (c *Client) GetRayJobPlacement(ctx context.Context, name, namespace string) ([]models.ClusterPlacement, error) {
+	binding, err := c.GetRayJobBinding(ctx, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return bindingPlacement(binding), nil
+}
+
+// bindingPlacement combines the scheduling result of a binding with the
+// per-cluster apply and health state the status controller aggregated
+func bindingPlacement(binding *workv1alpha2.ResourceBinding) []models.ClusterPlacement {
+	statuses := make(map[string]workv1alpha2.AggregatedStatusItem, len(binding.Status.AggregatedStatus))
+	for _, item := range binding.Status.AggregatedStatus {
+		statuses[item.ClusterName] = item
+	}
+
+	placement := make([]models.ClusterPlacement, 0, len(binding.Spec.Clusters))
+	for _, target := range binding.Spec.Clusters {
+		item := statuses[target.Name]
+		placement = append(placement, models.ClusterPlacement{
+			Cluster:  target.Name,
+			Replicas: target.Replicas,
+			Applied:  item.Applied,
+			Health:   string(item.Health),
+			Message:  item.AppliedMessage,
+		})
+	}
+	return placement
+}
+
+// applyPlacementOptions renders the requested weights, cluster label affinity,
+// tolerations and spread constraints into a policy placement
+func applyPlacementOptions(placement *policyv1alpha1.Placement, opts *models.Placement) {
+	if opts == nil {
+		return
+	}
+
+	if len(opts.ClusterLabels) > 0 {
+		placement.ClusterAffinity.LabelSelector = &metav1.LabelSelector{
+			MatchLabels: opts.ClusterLabels,
+		}
+	}
+
+	for _, toleration := range opts.ClusterTolerations {
+		placement.ClusterTolerations = append(placement.ClusterTolerations, corev1.Toleration{
+			Key:      toleration.Key,
+			Operator: corev1.TolerationOperator(toleration.Operator),
+			Value:    toleration.Value,
+			Effect:   corev1.TaintEffect(toleration.Effect),
+		})
+	}
+
+	for _, constraint := range opts.SpreadConstraints {
+		spread := policyv1alpha1.SpreadConstraint{
+			SpreadByField: policyv1alpha1.SpreadFieldValue(constraint.SpreadByField),
+			SpreadByLabel: constraint.SpreadByLabel,
+			MinGroups:     constraint.MinGroups,
+			MaxGroups:     constraint.MaxGroups,
+		}
+		if spread.SpreadByField == "" && spread.SpreadByLabel == "" {
+			spread.SpreadByField = policyv1alpha1.SpreadByFieldCluster
+		}
+		placement.SpreadConstraints = append(placement.SpreadConstraints, spread)
+	}
+
+	switch {
+	case len(opts.ClusterWeights) > 0:
+		clusters := opts.WeightedClusters()
+		weights := make([]policyv1alpha1.StaticClusterWeight, 0, len(clusters))
+		for _, cluster := range clusters {
+			weights = append(weights, policyv1alpha1.StaticClusterWeight{
+				TargetCluster: policyv1alpha1.ClusterAffinity{ClusterNames: []string{cluster}},
+				Weight:        opts.ClusterWeights[cluster],
+			})
+		}
+		placement.ReplicaScheduling.ReplicaDivisionPreference = policyv1alpha1.ReplicaDivisionPreferenceWeighted
+		placement.ReplicaScheduling.WeightPreference = &policyv1alpha1.ClusterPreferences{
+			StaticWeightList: weights,
+		}
+
+	case opts.DynamicWeight:
+		placement.ReplicaScheduling.ReplicaDivisionPreference = policyv1alpha1.ReplicaDivisionPreferenceWeighted
+		placement.ReplicaScheduling.WeightPreference = &policyv1alpha1.ClusterPreferences{
+			DynamicWeight: policyv1alpha1.DynamicWeightByAvailableReplicas,
+		}
+	}
+}
+
+// Cluster affinity groups of a placement with a preferred cluster
+const (
+	preferredAffinityName = "preferred"
+	fallbackAffinityName  = "fallback"
+)
+
+// preferCluster turns the cluster affinity into ordered affinity groups: the
+// preferred cluster first, then the clusters the workload may move to. Karmada
+// only tries the next group when the current one cannot be scheduled, and
+// does not allow a cluster affinity next to the groups, so each group keeps
+// its label and field selectors and excluded clusters.
+func preferCluster(placement *policyv1alpha1.Placement, preferred string, fallback []string) {
+	affinity := policyv1alpha1.ClusterAffinity{}
+	if placement.ClusterAffinity != nil {
+		affinity = *placement.ClusterAffinity.DeepCopy()
+	}
+	placement.ClusterAffinity = nil
+
+	term := func(name string, clusters []string) policyv1alpha1.ClusterAffinityTerm {
+		clusterAffinity := *affinity.DeepCopy()
+		clusterAffinity.ClusterNames = clusters
+		return policyv1alpha1.ClusterAffinityTerm{AffinityName: name, ClusterAffinity: clusterAffinity}
+	}
+	placement.ClusterAffinities = []policyv1alpha1.ClusterAffinityTerm{term(preferredAffinityName, []string{preferred})}
+	if len(fallback) > 0 {
+		placement.ClusterAffinities = append(placement.ClusterAffinities, term(fallbackAffinityName, fallback))
+	}
+}
@@ -0,0 +1,85 @@
+package karmada
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	configv1alpha1 "github.com/karmada-io/karmada/pkg/apis/config/v1alpha1"
+)
+
+// rayJobSuspendRetentionName is the interpreter customization keeping spec.suspend of member RayJobs
+const rayJobSuspendRetentionName = "rayjob-suspend-retention"
+
+// rayJobSuspendRetentionScript keeps the member cluster's spec.suspend when Karmada syncs the template
+const rayJobSuspendRetentionScript = `
+function Retain(desiredObj, observedObj)
+  if observedObj.spec ~= nil and observedObj.spec.suspend ~= nil then
+    desiredObj.spec.suspend = observedObj.spec.suspend
+  end
+  return desiredObj
+end
+`
+
+// EnsureRayJobSuspendRetention makes Karmada retain spec.suspend of RayJobs in member clusters.
+// Kueue admits a job by unsuspending it in the member cluster; without this Karmada
+// would sync the suspended template back and the job would never start.
+func (c *Client) EnsureRayJobSuspendRetention(ctx context.Context) error {
+	customizations := c.karmadaClient.ConfigV1alpha1().ResourceInterpreterCustomizations()
+
+	_, err := customizations.Get(ctx, rayJobSuspendRetentionName, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get resource interpreter customization: %w", err)
+	}
+
+	customization := &configv1alpha1.ResourceInterpreterCustomization{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: rayJobSuspendRetentionName,
+		},
+		Spec: configv1alpha1.ResourceInterpreterCustomizationSpec{
+			Target: configv1alpha1.CustomizationTarget{
+				APIVersion: "ray.io/v1",
+				Kind:       "RayJob",
+			},
+			Customizations: configv1alpha1.CustomizationRules{
+				Retention: &configv1alpha1.LocalValueRetention{
+					LuaScript: rayJobSuspendRetentionScript,
+				},
+			},
+		},
+	}
+
+	_, err = customizations.Create(ctx, customization, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create resource interpreter customization: %w", err)
+	}
+
+	log.Printf("Created resource interpreter customization %s", rayJobSuspendRetentionName)
+	return nil
+}
+
+// GetVolcanoPodGroupPhase gets the phase of a Volcano PodGroup in a member cluster.
+// A PodGroup that does not exist yet is reported as Pending.
+func (c *Client) GetVolcanoPodGroupPhase(ctx context.Context, clusterName, namespace, name string) (string, error) {
+	podGroup, err := c.GetObject(ctx, clusterName, podGroupGVK, namespace, name)
+	if err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return "Pending", nil
+		}
+		return "", err
+	}
+
+	phase, _, err := unstructured.NestedString(podGroup.Object, "status", "phase")
+	if err != nil {
+		return "", fmt.Errorf("failed to read PodGroup %s phase from cluster %s: %w", name, clusterName, err)
+	}
+	return phase, nil
+}
@@ -0,0 +1,232 @@
+package karmada
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+// Kinds the client reads and writes through the dynamic client
+var (
+	rayJobGVK       = schema.GroupVersionKind{Group: "ray.io", Version: "v1", Kind: rayJobKind}
+	pvcGVK          = corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim")
+	nodeGVK         = corev1.SchemeGroupVersion.WithKind("Node")
+	podGVK          = corev1.SchemeGroupVersion.WithKind("Pod")
+	eventGVK        = corev1.SchemeGroupVersion.WithKind("Event")
+	podGroupGVK     = schema.GroupVersionKind{Group: "scheduling.volcano.sh", Version: "v1beta1", Kind: "PodGroup"}
+	storageClassGVK = schema.GroupVersionKind{Group: "storage.k8s.io", Version: "v1", Kind: "StorageClass"}
+)
+
+// apiClient is a dynamic client with the RESTMapper of the same API server,
+// either the Karmada control plane or a member cluster behind the cluster proxy
+type apiClient struct {
+	dynamic dynamic.Interface
+	mapper  *restmapper.DeferredDiscoveryRESTMapper
+}
+
+func newAPIClient(dynamicClient dynamic.Interface, discoveryClient discovery.DiscoveryInterface) *apiClient {
+	return &apiClient{
+		dynamic: dynamicClient,
+		mapper:  restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+	}
+}
+
+// resource returns the dynamic resource serving a kind. The cached discovery
+// is refreshed once when the kind is unknown, e.g. after a CRD was installed.
+func (a *apiClient) resource(gvk schema.GroupVersionKind, namespace string) (dynamic.ResourceInterface, error) {
+	mapping, err := a.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		a.mapper.Reset()
+		mapping, err = a.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return a.dynamic.Resource(mapping.Resource).Namespace(namespace), nil
+	}
+	return a.dynamic.Resource(mapping.Resource), nil
+}
+
+// memberProxyPath is the Karmada cluster proxy in front of a member cluster's API server
+func memberProxyPath(clusterName string) string {
+	return fmt.Sprintf("/apis/cluster.karmada.io/v1alpha1/clusters/%s/proxy", clusterName)
+}
+
+// memberClients caches the API clients of member clusters by cluster name
+type memberClients struct {
+	mu      sync.Mutex
+	clients map[string]*apiClient
+}
+
+// member returns the API client of a member cluster, reached through the cluster proxy
+func (c *Client) member(clusterName string) (*apiClient, error) {
+	c.members.mu.Lock()
+	defer c.members.mu.Unlock()
+
+	if client, ok := c.members.clients[clusterName]; ok {
+		return client, nil
+	}
+
+	config := rest.CopyConfig(c.restConfig)
+	config.Host = strings.TrimSuffix(config.Host, "/") + memberProxyPath(clusterName)
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for cluster %s: %w", clusterName, err)
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client for cluster %s: %w", clusterName, err)
+	}
+
+	client := newAPIClient(dynamicClient, discoveryClient)
+	if c.members.clients == nil {
+		c.members.clients = map[string]*apiClient{}
+	}
+	c.members.clients[clusterName] = client
+	return client, nil
+}
+
+// api returns the Karmada control plane client for an empty cluster name, else the member cluster's
+func (c *Client) api(clusterName string) (*apiClient, error) {
+	if clusterName == "" {
+		return c.karmada, nil
+	}
+	return c.member(clusterName)
+}
+
+// where names the API server of a cluster in error messages
+func where(clusterName string) string {
+	if clusterName == "" {
+		return "Karmada"
+	}
+	return "cluster " + clusterName
+}
+
+// CreateObject creates an object in the Karmada control plane. Typed objects
+// without apiVersion and kind get them from the client-go scheme.
+func (c *Client) CreateObject(ctx context.Context, obj runtime.Object) (*unstructured.Unstructured, error) {
+	u, err := toUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	resource, err := c.karmada.resource(u.GroupVersionKind(), u.GetNamespace())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s %s/%s: %w", u.GetKind(), u.GetNamespace(), u.GetName(), err)
+	}
+	created, err := resource.Create(ctx, u, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s %s/%s in Karmada: %w", u.GetKind(), u.GetNamespace(), u.GetName(), err)
+	}
+	return created, nil
+}
+
+// GetObject gets an object from the Karmada control plane, or from a member
+// cluster when clusterName is set. Namespace is ignored for cluster-scoped kinds.
+func (c *Client) GetObject(ctx context.Context, clusterName string, gvk schema.GroupVersionKind, namespace, name string) (*unstructured.Unstructured, error) {
+	resource, err := c.resourceIn(clusterName, gvk, namespace)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := resource.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s from %s: %w", gvk.Kind, name, where(clusterName), err)
+	}
+	return obj, nil
+}
+
+// ListObjects lists objects of a kind in the Karmada control plane or a member cluster.
+// An empty namespace lists all namespaces.
+func (c *Client) ListObjects(ctx context.Context, clusterName string, gvk schema.GroupVersionKind, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	resource, err := c.resourceIn(clusterName, gvk, namespace)
+	if err != nil {
+		return nil, err
+	}
+	list, err := resource.List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s in %s: %w", gvk.Kind, where(clusterName), err)
+	}
+	return list, nil
+}
+
+// PatchObject patches an object in the Karmada control plane
+func (c *Client) PatchObject(ctx context.Context, gvk schema.GroupVersionKind, namespace, name string, patchType types.PatchType, data []byte) (*unstructured.Unstructured, error) {
+	resource, err := c.resourceIn("", gvk, namespace)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := resource.Patch(ctx, name, patchType, data, metav1.PatchOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch %s %s/%s in Karmada: %w", gvk.Kind, namespace, name, err)
+	}
+	return obj, nil
+}
+
+// DeleteObject deletes an object from the Karmada control plane
+func (c *Client) DeleteObject(ctx context.Context, gvk schema.GroupVersionKind, namespace, name string, opts metav1.DeleteOptions) error {
+	resource, err := c.resourceIn("", gvk, namespace)
+	if err != nil {
+		return err
+	}
+	if err := resource.Delete(ctx, name, opts); err != nil {
+		return fmt.Errorf("failed to delete %s %s/%s from Karmada: %w", gvk.Kind, namespace, name, err)
+	}
+	return nil
+}
+
+// resourceIn returns the dynamic resource serving a kind in the Karmada control plane or a member cluster
+func (c *Client) resourceIn(clusterName string, gvk schema.GroupVersionKind, namespace string) (dynamic.ResourceInterface, error) {
+	api, err := c.api(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	resource, err := api.resource(gvk, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s in %s: %w", gvk.Kind, where(clusterName), err)
+	}
+	return resource, nil
+}
+
+// toUnstructured converts a typed or unstructured object for the dynamic client
+func toUnstructured(obj runtime.Object) (*unstructured.Unstructured, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u, nil
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %T: %w", obj, err)
+	}
+	u := &unstructured.Unstructured{Object: content}
+	if u.GetKind() == "" {
+		gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the kind of %T: %w", obj, err)
+		}
+		u.SetGroupVersionKind(gvks[0])
+	}
+	return u, nil
+}
+
+// fromUnstructured converts an object read through the dynamic client to its typed form
+func fromUnstructured(u *unstructured.Unstructured, obj interface{}) error {
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+		return fmt.Errorf("failed to convert %s %s: %w", u.GetKind(), u.GetName(), err)
+	}
+	return nil
+}
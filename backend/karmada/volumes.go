@@ -0,0 +1,57 @@
+package karmada
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// selectedNodeAnnotation is set on a claim of a WaitForFirstConsumer storage
+// class once the scheduler placed a pod using it
+const selectedNodeAnnotation = "volume.kubernetes.io/selected-node"
+
+// ClaimStatus is the state of a PVC in a member cluster
+type ClaimStatus struct {
+	// Phase is Pending, Bound or Lost, and empty when the claim has not been
+	// propagated to the cluster yet
+	Phase string
+	// WaitForFirstConsumer is set when the claim's storage class only binds
+	// once a pod using it is scheduled
+	WaitForFirstConsumer bool
+	// PodScheduled is set once a pod using a WaitForFirstConsumer claim was
+	// scheduled, so the claim is being provisioned
+	PodScheduled bool
+}
+
+// GetClaimStatus gets the state of a PVC in a member cluster
+func (c *Client) GetClaimStatus(ctx context.Context, clusterName, namespace, name string) (*ClaimStatus, error) {
+	obj, err := c.GetObject(ctx, clusterName, pvcGVK, namespace, name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return &ClaimStatus{}, nil
+		}
+		return nil, err
+	}
+	var pvc corev1.PersistentVolumeClaim
+	if err := fromUnstructured(obj, &pvc); err != nil {
+		return nil, err
+	}
+
+	status := &ClaimStatus{Phase: string(pvc.Status.Phase)}
+	if pvc.Status.Phase != corev1.ClaimPending || pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return status, nil
+	}
+
+	// Only a pending claim's storage class tells whether it waits for its pods
+	class, err := c.GetObject(ctx, clusterName, storageClassGVK, "", *pvc.Spec.StorageClassName)
+	if err != nil {
+		return nil, err
+	}
+	bindingMode, _, _ := unstructured.NestedString(class.Object, "volumeBindingMode")
+	status.WaitForFirstConsumer = bindingMode == string(storagev1.VolumeBindingWaitForFirstConsumer)
+	status.PodScheduled = pvc.Annotations[selectedNodeAnnotation] != ""
+	return status, nil
+}
@@ -0,0 +1,82 @@
+package karmada
+
+import (
+	"log"
+
+	"k8s.io/client-go/tools/cache"
+
+	workv1alpha2 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha2"
+)
+
+// WatchHandler receives the changes reported by Watch. Handlers are called
+// from the informer goroutines and should only queue work.
+type WatchHandler struct {
+	// RayJobChanged is called when the ResourceBinding of a RayJob was added,
+	// updated or deleted: when Karmada scheduled or rescheduled it, or
+	// reflected a new status from a member cluster
+	RayJobChanged func(namespace, name string)
+	// ClusterReadinessChanged is called when a member cluster became ready or not ready
+	ClusterReadinessChanged func(change ClusterReadinessChange)
+}
+
+// Watch calls the handler with RayJob and member cluster changes until stop
+// is called. Without a started inventory there is nothing to watch and ok is
+// false; changes must then be found by polling.
+func (c *Client) Watch(handler WatchHandler) (stop func(), ok bool) {
+	inventory := c.Inventory()
+	if inventory == nil {
+		return nil, false
+	}
+	return inventory.Watch(handler), true
+}
+
+// Watch calls the handler with the changes of RayJob bindings and cluster
+// readiness until stop is called. Existing bindings are reported as added.
+func (i *Inventory) Watch(handler WatchHandler) (stop func()) {
+	var registration cache.ResourceEventHandlerRegistration
+	if handler.RayJobChanged != nil {
+		var err error
+		registration, err = i.bindingInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { bindingChanged(obj, handler.RayJobChanged) },
+			UpdateFunc: func(_, obj interface{}) { bindingChanged(obj, handler.RayJobChanged) },
+			DeleteFunc: func(obj interface{}) { bindingChanged(obj, handler.RayJobChanged) },
+		})
+		if err != nil {
+			log.Printf("Failed to watch resource bindings: %v", err)
+		}
+	}
+
+	changes, unsubscribe := i.Subscribe()
+	go func() {
+		for change := range changes {
+			if handler.ClusterReadinessChanged != nil {
+				handler.ClusterReadinessChanged(change)
+			}
+		}
+	}()
+
+	return func() {
+		if registration != nil {
+			if err := i.bindingInformer.RemoveEventHandler(registration); err != nil {
+				log.Printf("Failed to stop watching resource bindings: %v", err)
+			}
+		}
+		unsubscribe()
+	}
+}
+
+// bindingChanged reports the RayJob a changed ResourceBinding belongs to
+func bindingChanged(obj interface{}, rayJobChanged func(namespace, name string)) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	binding, ok := obj.(*workv1alpha2.ResourceBinding)
+	if !ok || binding.Spec.Resource.Kind != rayJobKind {
+		return
+	}
+	namespace := binding.Spec.Resource.Namespace
+	if namespace == "" {
+		namespace = binding.Namespace
+	}
+	rayJobChanged(namespace, binding.Spec.Resource.Name)
+}
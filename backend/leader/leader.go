@@ -0,0 +1,193 @@
+// Package leader elects the backend replica that runs the background loops,
+// such as the job monitor, through a Lease in the management cluster.
+package leader
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// Lease timings, as used by the Kubernetes controllers
+const (
+	leaseDuration = 15 * time.Second
+	renewDeadline = 10 * time.Second
+	retryPeriod   = 2 * time.Second
+)
+
+// Status describes the leader election of this replica
+type Status struct {
+	// Enabled is false when every replica runs the background loops
+	Enabled bool   `json:"enabled"`
+	Lease   string `json:"lease,omitempty"` // namespace/name
+	// Identity is the identity of this replica
+	Identity string `json:"identity"`
+	// Leader is the identity of the replica running the background loops;
+	// empty until a leader was observed
+	Leader   string `json:"leader"`
+	IsLeader bool   `json:"isLeader"`
+}
+
+// Elector runs the background loops on one replica at a time. The replica
+// holding the Lease leads; the others wait to take over when it stops renewing
+// it. Without a Lease every replica leads.
+type Elector struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+	identity  string
+
+	leaseDuration time.Duration
+	renewDeadline time.Duration
+	retryPeriod   time.Duration
+
+	mu      sync.Mutex
+	leader  string
+	leading bool
+	stopped bool
+	// running tracks the lead calls, so Run returns after them
+	running sync.WaitGroup
+	// term keeps the lead calls of successive terms from overlapping
+	term sync.Mutex
+}
+
+// New creates an elector for the Lease given as namespace/name in the
+// management cluster, or an elector that always leads when lease is empty
+func New(client kubernetes.Interface, lease, identity string) (*Elector, error) {
+	e := &Elector{
+		client:        client,
+		identity:      identity,
+		leaseDuration: leaseDuration,
+		renewDeadline: renewDeadline,
+		retryPeriod:   retryPeriod,
+	}
+	if lease == "" {
+		return e, nil
+	}
+	if identity == "" {
+		return nil, fmt.Errorf("leader election needs an identity for this replica")
+	}
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(lease)
+	if err != nil || name == "" {
+		return nil, fmt.Errorf("invalid leader election lease %q (expected namespace/name)", lease)
+	}
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	e.namespace = namespace
+	e.name = name
+	return e, nil
+}
+
+// Run calls lead each time this replica becomes the leader, with a context
+// that is cancelled when it stops leading, until ctx is done. The Lease is
+// released on the way out so another replica takes over at once. Run returns
+// once lead has returned.
+func (e *Elector) Run(ctx context.Context, lead func(ctx context.Context)) {
+	if e.name == "" {
+		e.lead(ctx, lead)
+		return
+	}
+
+	log.Printf("Electing the leader through lease %s/%s as %s", e.namespace, e.name, e.identity)
+	for ctx.Err() == nil {
+		elector, err := leaderelection.NewLeaderElector(e.config(lead))
+		if err != nil {
+			log.Printf("Failed to start leader election: %v", err)
+			break
+		}
+		// Returns when ctx is done or the lease was lost
+		elector.Run(ctx)
+	}
+
+	e.mu.Lock()
+	e.stopped = true
+	e.mu.Unlock()
+	e.running.Wait()
+}
+
+// config is the election configuration of one term
+func (e *Elector) config(lead func(ctx context.Context)) leaderelection.LeaderElectionConfig {
+	return leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta:  metav1.ObjectMeta{Namespace: e.namespace, Name: e.name},
+			Client:     e.client.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{Identity: e.identity},
+		},
+		LeaseDuration:   e.leaseDuration,
+		RenewDeadline:   e.renewDeadline,
+		RetryPeriod:     e.retryPeriod,
+		ReleaseOnCancel: true,
+		Name:            e.name,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) { e.lead(ctx, lead) },
+			OnStoppedLeading: func() {},
+			OnNewLeader: func(identity string) {
+				e.mu.Lock()
+				e.leader = identity
+				e.mu.Unlock()
+				log.Printf("Leader of lease %s/%s is %s", e.namespace, e.name, identity)
+			},
+		},
+	}
+}
+
+// lead calls lead for a term, unless the term or Run already ended
+func (e *Elector) lead(ctx context.Context, lead func(ctx context.Context)) {
+	e.mu.Lock()
+	if e.stopped || ctx.Err() != nil {
+		e.mu.Unlock()
+		return
+	}
+	e.running.Add(1)
+	e.mu.Unlock()
+	defer e.running.Done()
+
+	e.term.Lock()
+	defer e.term.Unlock()
+	if ctx.Err() != nil {
+		return
+	}
+
+	e.setLeading(true)
+	defer e.setLeading(false)
+	if e.name != "" {
+		log.Printf("Became the leader; running the background loops")
+		defer log.Printf("Stopped leading")
+	}
+	lead(ctx)
+}
+
+func (e *Elector) setLeading(leading bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.leading = leading
+	if leading {
+		e.leader = e.identity
+	}
+}
+
+// Status returns the leader election status of this replica
+func (e *Elector) Status() Status {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.name == "" {
+		return Status{Identity: e.identity, Leader: e.identity, IsLeader: true}
+	}
+	return Status{
+		Enabled:  true,
+		Lease:    e.namespace + "/" + e.name,
+		Identity: e.identity,
+		Leader:   e.leader,
+		IsLeader: e.leading,
+	}
+}
@@ -0,0 +1,107 @@
+package leader
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// newTestElector creates an elector with short lease timings
+func newTestElector(t *testing.T, client *fake.Clientset, identity string) *Elector {
+	t.Helper()
+	e, err := New(client, "ml-platform/backend-leader", identity)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	e.leaseDuration = time.Second
+	e.renewDeadline = 800 * time.Millisecond
+	e.retryPeriod = 100 * time.Millisecond
+	return e
+}
+
+// waitFor polls until done or fails the test
+func waitFor(t *testing.T, what string, done func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if done() {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func TestElectorHandsOverOnShutdown(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	first := newTestElector(t, client, "replica-1")
+	second := newTestElector(t, client, "replica-2")
+
+	leading := make(chan string, 10)
+	lead := func(identity string) func(ctx context.Context) {
+		return func(ctx context.Context) {
+			leading <- identity
+			<-ctx.Done()
+		}
+	}
+
+	firstCtx, stopFirst := context.WithCancel(context.Background())
+	firstDone := make(chan struct{})
+	go func() {
+		defer close(firstDone)
+		first.Run(firstCtx, lead("replica-1"))
+	}()
+	if got := <-leading; got != "replica-1" {
+		t.Fatalf("leader = %s, want replica-1", got)
+	}
+
+	secondCtx, stopSecond := context.WithCancel(context.Background())
+	defer stopSecond()
+	go second.Run(secondCtx, lead("replica-2"))
+	waitFor(t, "replica-2 to observe the leader", func() bool { return second.Status().Leader == "replica-1" })
+	if status := second.Status(); status.IsLeader || !status.Enabled || status.Lease != "ml-platform/backend-leader" {
+		t.Errorf("replica-2 status = %+v, want a follower", status)
+	}
+
+	// Shutting down releases the lease to the other replica
+	stopFirst()
+	<-firstDone
+	if first.Status().IsLeader {
+		t.Error("replica-1 still leads after Run returned")
+	}
+	select {
+	case got := <-leading:
+		if got != "replica-2" {
+			t.Errorf("leader = %s, want replica-2", got)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("replica-2 did not take over")
+	}
+	if status := second.Status(); !status.IsLeader || status.Leader != "replica-2" {
+		t.Errorf("replica-2 status = %+v, want the leader", status)
+	}
+}
+
+func TestElectorWithoutLeaseAlwaysLeads(t *testing.T) {
+	e, err := New(nil, "", "replica-1")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	e.Run(ctx, func(ctx context.Context) {
+		if status := e.Status(); !status.IsLeader || status.Enabled || status.Leader != "replica-1" {
+			t.Errorf("status = %+v, want leading without election", status)
+		}
+		cancel()
+	})
+}
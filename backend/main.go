@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -14,15 +16,28 @@ import (
 	"github.com/loiht2/ml-platform-training-job/backend/config"
 	"github.com/loiht2/ml-platform-training-job/backend/handlers"
 	"github.com/loiht2/ml-platform-training-job/backend/karmada"
+	"github.com/loiht2/ml-platform-training-job/backend/leader"
+	"github.com/loiht2/ml-platform-training-job/backend/models"
 	"github.com/loiht2/ml-platform-training-job/backend/monitor"
 	"github.com/loiht2/ml-platform-training-job/backend/repository"
 )
 
+const (
+	// inventorySyncTimeout bounds each attempt to fill a federation's informer cache
+	inventorySyncTimeout = 30 * time.Second
+	// inventoryRetryPeriod is how long to wait before retrying a cache that did not sync
+	inventoryRetryPeriod = time.Minute
+)
+
 func main() {
 	// Parse command line arguments
 	karmadaKubeconfig := flag.String("karmada-kubeconfig", os.Getenv("KARMADA_KUBECONFIG"), "Path to Karmada kubeconfig file")
 	mgmtKubeconfig := flag.String("mgmt-kubeconfig", os.Getenv("MGMT_KUBECONFIG"), "Path to management cluster kubeconfig file")
 	databaseURL := flag.String("database-url", os.Getenv("DATABASE_URL"), "Database connection URL")
+	settingsFile := flag.String("settings-file", os.Getenv("SETTINGS_FILE"), "Path to platform settings file (YAML or JSON, optional)")
+	settingsConfigMap := flag.String("settings-configmap", os.Getenv("SETTINGS_CONFIGMAP"), "Management cluster ConfigMap with platform settings, as namespace/name (optional)")
+	settingsSecret := flag.String("settings-secret", os.Getenv("SETTINGS_SECRET"), "Management cluster Secret with storage credentials, as namespace/name (optional)")
+	leaderElectionLease := flag.String("leader-election-lease", os.Getenv("LEADER_ELECTION_LEASE"), "Management cluster Lease electing the replica that runs the job monitor, as namespace/name (optional; without it every replica runs it)")
 	port := flag.String("port", getEnvOrDefault("PORT", "8080"), "Server port")
 	flag.Parse()
 
@@ -38,25 +53,66 @@ func main() {
 	}
 
 	// Initialize configuration
-	cfg, err := config.New(*karmadaKubeconfig, *mgmtKubeconfig, *databaseURL)
+	cfg, err := config.New(*karmadaKubeconfig, *mgmtKubeconfig, *databaseURL, *settingsFile, *settingsConfigMap, *settingsSecret)
 	if err != nil {
 		log.Fatalf("Failed to initialize configuration: %v", err)
 	}
 	defer cfg.Close()
 
+	// Informers and watches run until shutdown
+	watchCtx, stopWatches := context.WithCancel(context.Background())
+	defer stopWatches()
+
+	// Reload platform settings when their ConfigMap or Secret changes
+	if err := cfg.WatchSettings(watchCtx); err != nil {
+		log.Fatalf("Failed to watch platform settings: %v", err)
+	}
+
 	// Initialize database repository
 	repo := repository.NewRepository(cfg.DB)
 
-	// Initialize Karmada client
-	karmadaClient := karmada.NewClient(cfg.KarmadaClient, cfg.KarmadaK8sClient)
+	// Initialize a Karmada client per federation, reading clusters and
+	// bindings from an informer cache once it has synced. The caches sync in
+	// parallel; those that do not sync in time keep retrying in the background.
+	karmadaClients := map[string]karmada.Interface{}
+	var firstSyncs sync.WaitGroup
+	for _, federation := range cfg.Federations {
+		karmadaClient := karmada.NewClient(federation.Config, federation.KarmadaClient, federation.K8sClient, federation.DynamicClient)
+		firstSyncs.Add(1)
+		go runInventory(watchCtx, federation.Name, karmadaClient, firstSyncs.Done)
+		karmadaClients[federation.Name] = karmadaClient
+	}
+	firstSyncs.Wait()
+	federations := karmada.NewFederations(cfg.Settings.DefaultFederationName(), karmadaClients)
 
-	// Initialize and start job monitor (polls every 1 second)
-	jobMonitor := monitor.NewJobMonitor(repo, karmadaClient)
-	jobMonitor.Start()
-	defer jobMonitor.Stop()
+	// Run the job monitor (reconciles jobs as their Karmada bindings change)
+	// on the elected replica only; every replica serves the API
+	hostname, _ := os.Hostname()
+	elector, err := leader.New(cfg.MgmtClient, *leaderElectionLease, getEnvOrDefault("POD_NAME", hostname))
+	if err != nil {
+		log.Fatalf("Failed to set up leader election: %v", err)
+	}
+	var jobMonitor atomic.Pointer[monitor.JobMonitor]
+	electionDone := make(chan struct{})
+	go func() {
+		defer close(electionDone)
+		elector.Run(watchCtx, func(ctx context.Context) {
+			m := monitor.NewJobMonitor(repo, federations, cfg.Settings.MonitorFor())
+			m.Start()
+			jobMonitor.Store(m)
+			<-ctx.Done()
+			jobMonitor.Store(nil)
+			m.Stop()
+		})
+	}()
 
 	// Initialize handlers
-	handler := handlers.NewHandler(cfg, repo)
+	handler := handlers.NewHandler(cfg, repo, federations, elector, func() (models.MonitorStats, bool) {
+		if m := jobMonitor.Load(); m != nil {
+			return m.Stats(), true
+		}
+		return models.MonitorStats{}, false
+	})
 
 	// Setup Gin router
 	router := gin.Default()
@@ -70,26 +126,7 @@ func main() {
 	})
 
 	// API routes
-	api := router.Group("/api/v1")
-	{
-		// Training job routes
-		jobs := api.Group("/jobs")
-		{
-			jobs.POST("", handler.CreateTrainingJob)
-			jobs.GET("", handler.ListTrainingJobs)
-			jobs.GET("/:id", handler.GetTrainingJob)
-			jobs.DELETE("/:id", handler.DeleteTrainingJob)
-			jobs.GET("/:id/status", handler.GetTrainingJobStatus)
-			jobs.GET("/:id/logs", handler.GetTrainingJobLogs)
-		}
-
-		// Member cluster resources proxy
-		proxy := api.Group("/proxy")
-		{
-			proxy.GET("/clusters", handler.ListMemberClusters)
-			proxy.GET("/clusters/:cluster/resources", handler.GetClusterResources)
-		}
-	}
+	handler.RegisterRoutes(router.Group("/api/v1"))
 
 	// Create HTTP server with proper configuration
 	srv := &http.Server{
@@ -118,9 +155,10 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Stop job monitor first
+	// Stop job monitor first, handing the lease over to another replica
 	log.Println("Stopping job monitor...")
-	jobMonitor.Stop()
+	stopWatches()
+	<-electionDone
 
 	// Shutdown HTTP server
 	if err := srv.Shutdown(ctx); err != nil {
@@ -132,6 +170,31 @@ func main() {
 	log.Println("Server stopped gracefully")
 }
 
+// runInventory starts the informer cache of a federation's client, retrying
+// until it syncs or ctx is done. firstAttemptDone is called after the first
+// attempt, whether it synced or not.
+func runInventory(ctx context.Context, federation string, karmadaClient *karmada.Client, firstAttemptDone func()) {
+	for attempt := 1; ; attempt++ {
+		err := karmadaClient.StartInventory(ctx, inventorySyncTimeout)
+		if attempt == 1 {
+			firstAttemptDone()
+		}
+		if err == nil {
+			if attempt > 1 {
+				log.Printf("Federation %s inventory synced after %d attempts", federation, attempt)
+			}
+			return
+		}
+		log.Printf("Warning: federation %s reads Karmada directly until its inventory syncs (retrying in %s): %v", federation, inventoryRetryPeriod, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(inventoryRetryPeriod):
+		}
+	}
+}
+
 func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
@@ -1,35 +1,252 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
 
 // TrainingJobRequest represents the NEW request payload from frontend
 type TrainingJobRequest struct {
-	JobName            string              `json:"jobName" binding:"required"`
-	Priority           int                 `json:"priority"`
-	Algorithm          Algorithm           `json:"algorithm" binding:"required"`
-	Resources          Resources           `json:"resources" binding:"required"`
-	StoppingCondition  StoppingCondition   `json:"stoppingCondition"`
-	InputDataConfig    []InputDataConfig   `json:"inputDataConfig"`
-	OutputDataConfig   OutputDataConfig    `json:"outputDataConfig"`
-	Hyperparameters    HyperparametersMap  `json:"hyperparameters"`
-	CustomHyperparameters map[string]interface{} `json:"customHyperparameters"`
-	TargetClusters     []string            `json:"targetClusters"` // From frontend
-	Namespace          string              `json:"namespace"`      // Optional override
-	Entrypoint         string              `json:"entrypoint"`     // Optional override
-	HeadImage          string              `json:"headImage"`      // Optional override
-	WorkerImage        string              `json:"workerImage"`    // Optional override
-	PVCName            string              `json:"pvcName"`        // Optional PVC name
+	JobName               string                      `json:"jobName" binding:"required"`
+	Priority              int                         `json:"priority"`
+	Algorithm             Algorithm                   `json:"algorithm" binding:"required"`
+	Resources             Resources                   `json:"resources" binding:"required"`
+	StoppingCondition     StoppingCondition           `json:"stoppingCondition"`
+	InputDataConfig       []InputDataConfig           `json:"inputDataConfig"`
+	OutputDataConfig      OutputDataConfig            `json:"outputDataConfig"`
+	Hyperparameters       HyperparametersMap          `json:"hyperparameters"`
+	CustomHyperparameters map[string]interface{}      `json:"customHyperparameters"`
+	TargetClusters        []string                    `json:"targetClusters"`             // From frontend
+	Namespace             string                      `json:"namespace"`                  // Optional override
+	Entrypoint            string                      `json:"entrypoint"`                 // Optional override
+	HeadImage             string                      `json:"headImage"`                  // Optional override
+	WorkerImage           string                      `json:"workerImage"`                // Optional override
+	PVCName               string                      `json:"pvcName"`                    // Optional PVC name
+	StageInputData        bool                        `json:"stageInputData"`             // Copy input channels to local scratch before Ray starts
+	ClusterOverrides      map[string]ClusterOverrides `json:"clusterOverrides,omitempty"` // Per-cluster overrides, keyed by cluster name
+	Placement             *Placement                  `json:"placement,omitempty"`        // Multi-cluster placement; the server default applies when unset
+	DisableFailover       bool                        `json:"disableFailover,omitempty"`  // Keep the job on its clusters when they fail
+	Federation            string                      `json:"federation,omitempty"`       // Karmada control plane; defaults by namespace
+}
+
+// Placement controls how Karmada picks member clusters for a job and divides it between them
+type Placement struct {
+	ClusterWeights     map[string]int64    `json:"clusterWeights,omitempty"`     // Static weight per cluster name
+	DynamicWeight      bool                `json:"dynamicWeight,omitempty"`      // Weight clusters by the replicas they can still fit
+	ClusterLabels      map[string]string   `json:"clusterLabels,omitempty"`      // Only clusters with these labels, e.g. region: eu-west
+	ClusterTolerations []ClusterToleration `json:"clusterTolerations,omitempty"` // Allow clusters with matching taints
+	SpreadConstraints  []SpreadConstraint  `json:"spreadConstraints,omitempty"`
+}
+
+// ClusterToleration tolerates a taint on a Karmada Cluster
+type ClusterToleration struct {
+	Key      string `json:"key,omitempty"`
+	Operator string `json:"operator,omitempty"` // Exists or Equal (default)
+	Value    string `json:"value,omitempty"`
+	Effect   string `json:"effect,omitempty"` // NoSchedule or NoExecute; empty matches both
+}
+
+// SpreadConstraint bounds the number of cluster groups a job is spread over.
+// Groups are clusters, or clusters sharing a region, zone, provider or label.
+type SpreadConstraint struct {
+	SpreadByField string `json:"spreadByField,omitempty"` // cluster, region, zone or provider
+	SpreadByLabel string `json:"spreadByLabel,omitempty"` // Cluster label key; exclusive with spreadByField
+	MinGroups     int    `json:"minGroups,omitempty"`
+	MaxGroups     int    `json:"maxGroups,omitempty"`
+}
+
+// WeightedClusters returns the names of the clusters given a weight, sorted
+func (p *Placement) WeightedClusters() []string {
+	clusters := make([]string, 0, len(p.ClusterWeights))
+	for cluster := range p.ClusterWeights {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+	return clusters
+}
+
+// Validate checks the placement for combinations Karmada would reject
+func (p *Placement) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if len(p.ClusterWeights) > 0 && p.DynamicWeight {
+		return fmt.Errorf("clusterWeights and dynamicWeight are mutually exclusive")
+	}
+	for cluster, weight := range p.ClusterWeights {
+		if weight <= 0 {
+			return fmt.Errorf("clusterWeights.%s must be positive", cluster)
+		}
+	}
+	for key, value := range p.ClusterLabels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("clusterLabels key %q: %s", key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("clusterLabels value %q: %s", value, strings.Join(errs, "; "))
+		}
+	}
+	for _, toleration := range p.ClusterTolerations {
+		switch toleration.Operator {
+		case "", "Equal":
+		case "Exists":
+			if toleration.Value != "" {
+				return fmt.Errorf("clusterTolerations: value must be empty with operator Exists")
+			}
+		default:
+			return fmt.Errorf("clusterTolerations: unsupported operator %q (expected Exists or Equal)", toleration.Operator)
+		}
+		switch toleration.Effect {
+		case "", "NoSchedule", "NoExecute":
+		default:
+			return fmt.Errorf("clusterTolerations: unsupported effect %q (expected NoSchedule or NoExecute)", toleration.Effect)
+		}
+	}
+
+	byCluster, byOther := false, false
+	for _, constraint := range p.SpreadConstraints {
+		switch {
+		case constraint.SpreadByField != "" && constraint.SpreadByLabel != "":
+			return fmt.Errorf("spreadConstraints: spreadByField and spreadByLabel are mutually exclusive")
+		case constraint.SpreadByLabel != "":
+			byOther = true
+		case constraint.SpreadByField == "" || constraint.SpreadByField == "cluster":
+			byCluster = true
+		case constraint.SpreadByField == "region" || constraint.SpreadByField == "zone" || constraint.SpreadByField == "provider":
+			byOther = true
+		default:
+			return fmt.Errorf("spreadConstraints: unsupported spreadByField %q (expected cluster, region, zone or provider)", constraint.SpreadByField)
+		}
+		if constraint.MinGroups < 0 || constraint.MaxGroups < 0 {
+			return fmt.Errorf("spreadConstraints: minGroups and maxGroups must not be negative")
+		}
+		if constraint.MaxGroups > 0 && constraint.MinGroups > constraint.MaxGroups {
+			return fmt.Errorf("spreadConstraints: minGroups must not exceed maxGroups")
+		}
+	}
+	if byOther && !byCluster {
+		// Karmada only spreads by region, zone, provider or label together with a cluster constraint
+		return fmt.Errorf("spreadConstraints: spreading by region, zone, provider or label also requires a cluster constraint")
+	}
+	return nil
+}
+
+// ClusterOverrides adapts a job to one member cluster. They are applied by a
+// Karmada OverridePolicy when the job is propagated to that cluster.
+type ClusterOverrides struct {
+	ImageRegistry    string            `json:"imageRegistry,omitempty"`    // Replaces the registry of every container image
+	NodeSelector     map[string]string `json:"nodeSelector,omitempty"`     // Added to the head and worker pods
+	StorageClassName string            `json:"storageClassName,omitempty"` // Storage class of the job's PVC
+	Env              map[string]string `json:"env,omitempty"`              // Added to the head and worker containers
+}
+
+// IsEmpty reports whether the overrides change nothing
+func (o ClusterOverrides) IsEmpty() bool {
+	return o.ImageRegistry == "" && len(o.NodeSelector) == 0 && o.StorageClassName == "" && len(o.Env) == 0
+}
+
+// Merge returns the overrides with other applied on top; other wins on conflicts
+func (o ClusterOverrides) Merge(other ClusterOverrides) ClusterOverrides {
+	merged := ClusterOverrides{
+		ImageRegistry:    o.ImageRegistry,
+		StorageClassName: o.StorageClassName,
+		NodeSelector:     mergeStringMaps(o.NodeSelector, other.NodeSelector),
+		Env:              mergeStringMaps(o.Env, other.Env),
+	}
+	if other.ImageRegistry != "" {
+		merged.ImageRegistry = other.ImageRegistry
+	}
+	if other.StorageClassName != "" {
+		merged.StorageClassName = other.StorageClassName
+	}
+	return merged
+}
+
+// Validate checks the overrides for values Kubernetes would reject
+func (o ClusterOverrides) Validate() error {
+	if strings.HasSuffix(o.ImageRegistry, "/") {
+		return fmt.Errorf("imageRegistry must not end with /")
+	}
+	for key, value := range o.NodeSelector {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("nodeSelector key %q: %s", key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("nodeSelector value %q: %s", value, strings.Join(errs, "; "))
+		}
+	}
+	if o.StorageClassName != "" {
+		if errs := validation.IsDNS1123Subdomain(o.StorageClassName); len(errs) > 0 {
+			return fmt.Errorf("storageClassName %q: %s", o.StorageClassName, strings.Join(errs, "; "))
+		}
+	}
+	for name := range o.Env {
+		if errs := validation.IsEnvVarName(name); len(errs) > 0 {
+			return fmt.Errorf("env %q: %s", name, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+func mergeStringMaps(base, overlay map[string]string) map[string]string {
+	if len(base) == 0 && len(overlay) == 0 {
+		return nil
+	}
+	merged := make(map[string]string, len(base)+len(overlay))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overlay {
+		merged[k] = v
+	}
+	return merged
 }
 
 type Algorithm struct {
-	Source        string `json:"source"`        // "builtin" or "custom"
-	AlgorithmName string `json:"algorithmName"` // "xgboost", "tensorflow", etc.
+	Source        string      `json:"source"`               // "builtin" or "custom"
+	AlgorithmName string      `json:"algorithmName"`        // "xgboost", "tensorflow", etc.
+	Image         string      `json:"image,omitempty"`      // Custom only: image for head and workers
+	Entrypoint    string      `json:"entrypoint,omitempty"` // Custom only: command run from the code directory
+	Code          *CustomCode `json:"code,omitempty"`       // Custom only: where the training code comes from
+}
+
+// CustomCode describes where the code of a "custom" algorithm is fetched from.
+// At most one source may be set; when none is set the code must be baked into the image.
+type CustomCode struct {
+	Git    *GitCodeSource    `json:"git,omitempty"`
+	S3     *S3CodeSource     `json:"s3,omitempty"`
+	Inline *InlineCodeSource `json:"inline,omitempty"`
+}
+
+type GitCodeSource struct {
+	Repository string `json:"repository"`     // Clone URL, e.g. https://github.com/org/repo.git
+	Ref        string `json:"ref"`            // Branch, tag or commit SHA (defaults to HEAD)
+	Path       string `json:"path,omitempty"` // Optional sub-directory holding the code
+}
+
+type S3CodeSource struct {
+	Endpoint string `json:"endpoint"`
+	Bucket   string `json:"bucket"`
+	Key      string `json:"key"` // .zip, .tar or .tar.gz archive
+}
+
+type InlineCodeSource struct {
+	FileName string `json:"fileName"` // Defaults to main.py
+	Script   string `json:"script"`
 }
 
 type Resources struct {
 	InstanceResources InstanceResources `json:"instanceResources"`
 	InstanceCount     int               `json:"instanceCount"`
 	VolumeSizeGB      int               `json:"volumeSizeGB"`
+	StorageClassName  string            `json:"storageClassName,omitempty"` // StorageClass of the job's PVC
+	VolumeAccessMode  string            `json:"volumeAccessMode,omitempty"` // Access mode of the job's PVC (default ReadWriteMany)
+	SharedMemoryGiB   int               `json:"sharedMemoryGiB,omitempty"`  // In-memory /dev/shm size per pod
+	ScratchVolumeGiB  int               `json:"scratchVolumeGiB,omitempty"` // Ephemeral scratch volume per pod
 }
 
 type InstanceResources struct {
@@ -53,7 +270,8 @@ type InputDataConfig struct {
 }
 
 type OutputDataConfig struct {
-	ArtifactURI string `json:"artifactUri"`
+	ArtifactURI string `json:"artifactUri"`        // file://, storage:// or s3://bucket/prefix
+	Endpoint    string `json:"endpoint,omitempty"` // S3/MinIO endpoint for s3:// URIs (defaults to the first input channel's)
 }
 
 type HyperparametersMap struct {
@@ -105,42 +323,228 @@ type XGBoostHyperparameters struct {
 
 // TrainingJobResponse represents the response sent to frontend
 type TrainingJobResponse struct {
-	ID        string                 `json:"id"`
-	JobName   string                 `json:"jobName"`
-	Namespace string                 `json:"namespace"`
-	Algorithm string                 `json:"algorithm"`
-	Priority  int                    `json:"priority"`
-	Request   *TrainingJobRequest    `json:"request,omitempty"` // Full original request
-	Status    string                 `json:"status"`
-	Message   string                 `json:"message"`
-	CreatedAt time.Time              `json:"createdAt"`
-	UpdatedAt time.Time              `json:"updatedAt"`
+	ID               string              `json:"id"`
+	JobName          string              `json:"jobName"`
+	Namespace        string              `json:"namespace"`
+	Federation       string              `json:"federation,omitempty"` // Karmada control plane the job runs in
+	Algorithm        string              `json:"algorithm"`
+	Priority         int                 `json:"priority"`
+	Request          *TrainingJobRequest `json:"request,omitempty"` // Full original request
+	Status           string              `json:"status"`
+	Message          string              `json:"message"`
+	Admission        string              `json:"admission,omitempty"`        // Queued/Admitted when the job goes through a queue
+	ArtifactLocation string              `json:"artifactLocation,omitempty"` // Set once the job succeeds
+	Placement        []ClusterPlacement  `json:"placement,omitempty"`        // Where Karmada scheduled the job
+	FailoverEvents   []FailoverEvent     `json:"failoverEvents,omitempty"`   // Cluster failures that affected the job
+	Resources        []ResourceRef       `json:"resources,omitempty"`        // Kubernetes objects created for the job
+	CreatedAt        time.Time           `json:"createdAt"`
+	UpdatedAt        time.Time           `json:"updatedAt"`
 }
 
 // JobStatus represents the status of a training job
 type JobStatus struct {
-	Phase              string    `json:"phase"`
-	Reason             string    `json:"reason"`
-	Message            string    `json:"message"`
-	Active             int32     `json:"active"`
-	Succeeded          int32     `json:"succeeded"`
-	Failed             int32     `json:"failed"`
-	StartTime          time.Time `json:"startTime,omitempty"`
-	CompletionTime     time.Time `json:"completionTime,omitempty"`
-	ClusterDistribution map[string]int32 `json:"clusterDistribution,omitempty"` // Pods per cluster
+	Phase               string             `json:"phase"`
+	Reason              string             `json:"reason"`
+	Message             string             `json:"message"`
+	Admission           string             `json:"admission,omitempty"` // Queued/Admitted when the job goes through a queue
+	Active              int32              `json:"active"`
+	Succeeded           int32              `json:"succeeded"`
+	Failed              int32              `json:"failed"`
+	StartTime           time.Time          `json:"startTime,omitempty"`
+	CompletionTime      time.Time          `json:"completionTime,omitempty"`
+	ClusterDistribution map[string]int32   `json:"clusterDistribution,omitempty"` // Pods per cluster
+	Clusters            []ClusterJobStatus `json:"clusters,omitempty"`            // Status of the job in each cluster it is placed on
+}
+
+// ClusterJobStatus is the status of a job's RayJob in one member cluster
+type ClusterJobStatus struct {
+	Cluster          string `json:"cluster"`
+	Phase            string `json:"phase"` // Pending, Queued, Running, Succeeded, Failed or Unknown
+	Message          string `json:"message,omitempty"`
+	Admission        string `json:"admission,omitempty"`           // Queued/Admitted when the job goes through a queue
+	JobStatus        string `json:"jobStatus,omitempty"`           // RayJob status.jobStatus
+	DeploymentStatus string `json:"jobDeploymentStatus,omitempty"` // RayJob status.jobDeploymentStatus
+	StartTime        string `json:"startTime,omitempty"`
+	EndTime          string `json:"endTime,omitempty"`
+}
+
+// ClusterPlacement is one member cluster Karmada scheduled a job to,
+// as recorded in the job's ResourceBinding
+type ClusterPlacement struct {
+	Cluster  string `json:"cluster"`
+	Replicas int32  `json:"replicas"`          // Replicas Karmada assigned; 0 when the RayJob is not divided
+	Applied  bool   `json:"applied"`           // Whether the RayJob was created in the cluster
+	Health   string `json:"health,omitempty"`  // Healthy, Unhealthy or Unknown
+	Message  string `json:"message,omitempty"` // Why applying failed
+}
+
+// Failover event reasons
+const (
+	FailoverClusterNotReady  = "ClusterNotReady"
+	FailoverClusterRecovered = "ClusterRecovered"
+	FailoverRescheduled      = "Rescheduled"
+)
+
+// FailoverEvent records a readiness change of a cluster hosting a job, or the
+// job being moved to other clusters
+type FailoverEvent struct {
+	Time         time.Time `json:"time"`
+	Reason       string    `json:"reason"`
+	FromClusters []string  `json:"fromClusters,omitempty"`
+	ToClusters   []string  `json:"toClusters,omitempty"`
+	Message      string    `json:"message,omitempty"`
+}
+
+// KarmadaEventSource is the cluster name job events from the Karmada control plane are tagged with
+const KarmadaEventSource = "karmada"
+
+// JobEvent is a Kubernetes Event about one of a job's objects
+type JobEvent struct {
+	Time    time.Time `json:"time"`
+	Cluster string    `json:"cluster"` // Member cluster, or "karmada" for the control plane
+	Kind    string    `json:"kind"`    // Kind of the object the event is about
+	Name    string    `json:"name"`
+	Type    string    `json:"type"` // Normal or Warning
+	Reason  string    `json:"reason"`
+	Message string    `json:"message"`
+	Count   int32     `json:"count,omitempty"` // Times the event occurred
+}
+
+// JobEventsResponse is the event timeline of a job, oldest first
+type JobEventsResponse struct {
+	JobID  string            `json:"jobId"`
+	Events []JobEvent        `json:"events"`
+	Errors map[string]string `json:"errors,omitempty"` // Clusters whose events could not be read
+}
+
+// ResourceRef identifies a Kubernetes object created for a job in the Karmada control plane
+type ResourceRef struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+// Deletion results
+const (
+	DeletionDeleted = "Deleted" // Gone from Karmada and all member clusters
+	DeletionPending = "Pending" // Deleted, but still being removed when the wait ended
+	DeletionFailed  = "Failed"  // The delete request itself failed
+)
+
+// DeletionResult is the outcome of deleting one object of a job
+type DeletionResult struct {
+	ResourceRef
+	Result   string   `json:"result"`
+	Clusters []string `json:"clusters,omitempty"` // Member clusters still holding a copy
+	Error    string   `json:"error,omitempty"`
 }
 
 // ClusterInfo represents member cluster information
 type ClusterInfo struct {
-	Name   string `json:"name"`
-	Ready  bool   `json:"ready"`
-	Region string `json:"region,omitempty"`
-	Zone   string `json:"zone,omitempty"`
+	Name              string            `json:"name"`
+	Ready             bool              `json:"ready"`
+	Region            string            `json:"region,omitempty"`
+	Zone              string            `json:"zone,omitempty"`
+	Provider          string            `json:"provider,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	KubernetesVersion string            `json:"kubernetesVersion,omitempty"`
+	Nodes             *NodeCounts       `json:"nodes,omitempty"`
+	Capacity          *ClusterCapacity  `json:"capacity,omitempty"`
+}
+
+// FederationClusters lists the member clusters of one Karmada control plane
+type FederationClusters struct {
+	Federation string        `json:"federation"`
+	Default    bool          `json:"default,omitempty"`
+	Clusters   []ClusterInfo `json:"clusters"`
+	Error      string        `json:"error,omitempty"` // Set when the control plane could not be reached
+}
+
+// NodeCounts summarizes the nodes of a member cluster
+type NodeCounts struct {
+	Total int32 `json:"total"`
+	Ready int32 `json:"ready"`
+}
+
+// ClusterCapacity compares what a member cluster can run with what is already
+// requested there, as summarized by Karmada
+type ClusterCapacity struct {
+	Allocatable ResourceAmounts `json:"allocatable"`
+	Allocated   ResourceAmounts `json:"allocated"`  // Requested by running pods
+	Allocating  ResourceAmounts `json:"allocating"` // Requested by pods being scheduled
+	Available   ResourceAmounts `json:"available"`  // Allocatable minus allocated and allocating
+	GPUProducts []GPUProduct    `json:"gpuProducts,omitempty"`
+	// GPUProductsError is set when the GPU nodes could not be read through the cluster proxy
+	GPUProductsError string `json:"gpuProductsError,omitempty"`
+}
+
+// ResourceAmounts is an amount of the resources training jobs request
+type ResourceAmounts struct {
+	CPUCores  float64 `json:"cpuCores"`
+	MemoryGiB float64 `json:"memoryGiB"`
+	GPUs      int64   `json:"gpus"`
+	Pods      int64   `json:"pods"`
+}
+
+// GPUProduct counts the GPUs of one product (nvidia.com/gpu.product node label) in a cluster
+type GPUProduct struct {
+	Product string `json:"product"`
+	Nodes   int    `json:"nodes"`
+	GPUs    int64  `json:"gpus"` // Allocatable GPUs on those nodes
+}
+
+// ClusterResources is a page of objects listed from one member cluster
+type ClusterResources struct {
+	Cluster   string                   `json:"cluster"`
+	Kind      string                   `json:"kind,omitempty"`
+	Count     int                      `json:"count"`
+	Continue  string                   `json:"continue,omitempty"` // Pass as "continue" to get the next page
+	Resources []map[string]interface{} `json:"resources"`
+	Error     string                   `json:"error,omitempty"` // Set when the cluster could not be listed
 }
 
 // ClusterResourcesResponse represents resources in a member cluster
 type ClusterResourcesResponse struct {
-	Cluster   string                 `json:"cluster"`
-	Namespace string                 `json:"namespace"`
+	Cluster   string                   `json:"cluster"`
+	Namespace string                   `json:"namespace"`
 	Resources []map[string]interface{} `json:"resources"`
 }
+
+// ImportRayJobRequest carries an existing RayJob manifest to bring into the platform
+type ImportRayJobRequest struct {
+	Manifest       string   `json:"manifest" binding:"required"` // RayJob YAML or JSON
+	Submit         bool     `json:"submit"`                      // Submit the mapped request instead of only returning it
+	TargetClusters []string `json:"targetClusters"`              // Placement for the imported job
+	Federation     string   `json:"federation,omitempty"`        // Karmada control plane for the imported job
+}
+
+// ImportRayJobResponse returns the mapped request and what could not be mapped
+type ImportRayJobResponse struct {
+	Request  *TrainingJobRequest  `json:"request"`
+	Unmapped []string             `json:"unmapped"`      // Manifest fields the request cannot express
+	Job      *TrainingJobResponse `json:"job,omitempty"` // Set when the request was submitted
+}
+
+// MonitorStats describes the load of the job monitor, to size its workers and intervals
+type MonitorStats struct {
+	Workers            int      `json:"workers"`
+	WatchedFederations []string `json:"watchedFederations"`
+	PolledFederations  []string `json:"polledFederations"`
+	ResyncSeconds      int      `json:"resyncSeconds"`
+	PollSeconds        int      `json:"pollSeconds"`
+
+	// Backlog is the number of jobs waiting for a worker
+	Backlog int `json:"backlog"`
+	// Retrying is the number of jobs whose status could not be read, waiting out their backoff
+	Retrying int `json:"retrying"`
+
+	// LastResync is when the latest resync or poll queued the active jobs; nil before the first
+	LastResync           *time.Time `json:"lastResync,omitempty"`
+	LastResyncSeconds    float64    `json:"lastResyncSeconds"`
+	Reconciles           int64      `json:"reconciles"`
+	ReconcileFailures    int64      `json:"reconcileFailures"`
+	LastReconcileSeconds float64    `json:"lastReconcileSeconds"`
+	MaxReconcileSeconds  float64    `json:"maxReconcileSeconds"`
+	AvgReconcileSeconds  float64    `json:"avgReconcileSeconds"`
+}
@@ -0,0 +1,96 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/loiht2/ml-platform-training-job/backend/config"
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+	"github.com/loiht2/ml-platform-training-job/backend/repository"
+)
+
+// deletionCheckTimeout bounds how long one check waits for the resources of a
+// deleting job to go; whatever is left is checked again on the next poll
+const deletionCheckTimeout = 5 * time.Second
+
+// deletionLoop finishes the deletion of jobs whose resources were still being
+// removed, or failed to delete, when the job was deleted
+func (m *JobMonitor) deletionLoop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.pollPeriod())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopChan:
+			return
+		case <-ticker.C:
+			m.finishDeletions()
+		}
+	}
+}
+
+// finishDeletions deletes the resources left by every deleting job again and
+// removes the jobs whose resources are all gone
+func (m *JobMonitor) finishDeletions() {
+	jobs, err := m.repo.ListDeletingJobs()
+	if err != nil {
+		log.Printf("Failed to list deleting jobs: %v", err)
+		return
+	}
+	for i := range jobs {
+		select {
+		case <-m.stopChan:
+			return
+		default:
+		}
+		m.finishDeletion(&jobs[i])
+	}
+}
+
+// finishDeletion deletes the resources of a deleting job and removes the job
+// once every one is reported deleted
+func (m *JobMonitor) finishDeletion(job *config.TrainingJob) {
+	karmadaClient, err := m.federations.Get(m.jobFederation(job))
+	if err != nil {
+		log.Printf("Skipping deletion of job %s: %v", job.ID, err)
+		return
+	}
+	resources, err := repository.JobResources(job)
+	if err != nil {
+		log.Printf("Failed to get resources of job %s: %v", job.ID, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), deletionCheckTimeout)
+	defer cancel()
+
+	failed, pending := 0, 0
+	for _, result := range karmadaClient.DeleteJobResources(ctx, resources) {
+		switch result.Result {
+		case models.DeletionFailed:
+			failed++
+		case models.DeletionPending:
+			pending++
+		}
+	}
+
+	if failed > 0 || pending > 0 {
+		message := fmt.Sprintf("Cleanup incomplete: %d of %d resources failed to delete, %d still being removed; deletion continues in the background", failed, len(resources), pending)
+		if message != job.Message {
+			if err := m.repo.UpdateTrainingJobStatus(job.ID, "Deleting", message); err != nil {
+				log.Printf("Failed to update job status: %v", err)
+			}
+		}
+		return
+	}
+
+	if err := m.repo.DeleteTrainingJob(job.ID); err != nil {
+		log.Printf("Failed to delete training job %s from database: %v", job.ID, err)
+		return
+	}
+	log.Printf("Job %s deleted: all of its resources are gone", job.ID)
+}
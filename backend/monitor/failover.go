@@ -0,0 +1,203 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loiht2/ml-platform-training-job/backend/config"
+	"github.com/loiht2/ml-platform-training-job/backend/karmada"
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// clusterReadinessChanges lists the member clusters of a federation and
+// returns those whose readiness changed since it was last seen, with their
+// new readiness. The first call only records the baseline.
+func (m *JobMonitor) clusterReadinessChanges(federation string) map[string]bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	karmadaClient, err := m.federations.Get(federation)
+	if err != nil {
+		return nil
+	}
+	clusters, err := karmadaClient.ListMemberClusters(ctx)
+	if err != nil {
+		log.Printf("Failed to list member clusters of federation %s: %v", federation, err)
+		return nil
+	}
+
+	changes := map[string]bool{}
+	for _, cluster := range clusters {
+		if m.setClusterReady(federation, cluster.Name, cluster.Ready) {
+			changes[cluster.Name] = cluster.Ready
+		}
+	}
+	return changes
+}
+
+// clusterReadinessChanged records a readiness change a watch reported and
+// queues the jobs placed on the cluster
+func (m *JobMonitor) clusterReadinessChanged(federation string, change karmada.ClusterReadinessChange) {
+	if !m.setClusterReady(federation, change.Cluster, change.Ready) {
+		return
+	}
+
+	jobs, err := m.repo.ListActiveJobs()
+	if err != nil {
+		log.Printf("Failed to list active jobs: %v", err)
+		return
+	}
+	changes := map[string]bool{change.Cluster: change.Ready}
+	for i := range jobs {
+		if m.jobFederation(&jobs[i]) != federation {
+			continue
+		}
+		for _, cluster := range placedClusters(jobs[i].Placement) {
+			if cluster == change.Cluster {
+				m.recordClusterReadiness(&jobs[i], changes)
+				m.queue.Add(jobKey{federation: federation, namespace: jobs[i].Namespace, name: jobs[i].JobName})
+				break
+			}
+		}
+	}
+}
+
+// setClusterReady records the readiness of a member cluster and reports
+// whether it changed from the readiness last seen
+func (m *JobMonitor) setClusterReady(federation, cluster string, ready bool) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	clusterReady, ok := m.clusterReady[federation]
+	if !ok {
+		clusterReady = map[string]bool{}
+		m.clusterReady[federation] = clusterReady
+	}
+	previous, known := clusterReady[cluster]
+	clusterReady[cluster] = ready
+	if !known || previous == ready {
+		return false
+	}
+	log.Printf("Member cluster %s of federation %s readiness changed: ready=%t", cluster, federation, ready)
+	return true
+}
+
+// recordClusterReadiness adds a failover event to a job when clusters it is placed on failed or recovered
+func (m *JobMonitor) recordClusterReadiness(job *config.TrainingJob, changes map[string]bool) {
+	if len(changes) == 0 {
+		return
+	}
+
+	var notReady, recovered []string
+	for _, cluster := range placedClusters(job.Placement) {
+		ready, changed := changes[cluster]
+		switch {
+		case !changed:
+		case ready:
+			recovered = append(recovered, cluster)
+		default:
+			notReady = append(notReady, cluster)
+		}
+	}
+
+	if len(notReady) > 0 {
+		message := "Karmada moves the job to a healthy cluster once the cluster toleration expires"
+		if req, err := jobRequest(job); err == nil && req.DisableFailover {
+			message = "Failover is disabled for this job; it waits for the clusters to recover"
+		}
+		m.appendFailoverEvent(job.ID, models.FailoverEvent{
+			Reason:       models.FailoverClusterNotReady,
+			FromClusters: notReady,
+			Message:      fmt.Sprintf("Cluster %s became NotReady. %s", strings.Join(notReady, ", "), message),
+		})
+	}
+	if len(recovered) > 0 {
+		m.appendFailoverEvent(job.ID, models.FailoverEvent{
+			Reason:       models.FailoverClusterRecovered,
+			FromClusters: recovered,
+			Message:      fmt.Sprintf("Cluster %s is Ready again", strings.Join(recovered, ", ")),
+		})
+	}
+}
+
+// recordReschedule adds a failover event when Karmada moved a job off some of its clusters
+func (m *JobMonitor) recordReschedule(job *config.TrainingJob, placement []models.ClusterPlacement) {
+	previous := placedClusters(job.Placement)
+	if len(previous) == 0 {
+		// First placement
+		return
+	}
+
+	current := make([]string, 0, len(placement))
+	for _, p := range placement {
+		current = append(current, p.Cluster)
+	}
+	removed, added := clusterDiff(previous, current)
+	if len(removed) == 0 {
+		return
+	}
+
+	message := fmt.Sprintf("Job moved off %s", strings.Join(removed, ", "))
+	if len(added) > 0 {
+		message += fmt.Sprintf(" to %s", strings.Join(added, ", "))
+	}
+	m.appendFailoverEvent(job.ID, models.FailoverEvent{
+		Reason:       models.FailoverRescheduled,
+		FromClusters: removed,
+		ToClusters:   added,
+		Message:      message,
+	})
+}
+
+func (m *JobMonitor) appendFailoverEvent(jobID string, event models.FailoverEvent) {
+	event.Time = time.Now()
+	if err := m.repo.AppendFailoverEvent(jobID, event); err != nil {
+		log.Printf("Failed to record failover event for job %s: %v", jobID, err)
+		return
+	}
+	log.Printf("Job %s failover event %s: %s", jobID, event.Reason, event.Message)
+}
+
+// placedClusters returns the cluster names of a recorded placement
+func placedClusters(placementJSON string) []string {
+	if placementJSON == "" {
+		return nil
+	}
+	var placement []models.ClusterPlacement
+	if err := json.Unmarshal([]byte(placementJSON), &placement); err != nil {
+		return nil
+	}
+	clusters := make([]string, 0, len(placement))
+	for _, p := range placement {
+		clusters = append(clusters, p.Cluster)
+	}
+	return clusters
+}
+
+// clusterDiff returns the clusters only in before and only in after, sorted
+func clusterDiff(before, after []string) (removed, added []string) {
+	inBefore := map[string]bool{}
+	for _, c := range before {
+		inBefore[c] = true
+	}
+	inAfter := map[string]bool{}
+	for _, c := range after {
+		inAfter[c] = true
+		if !inBefore[c] {
+			added = append(added, c)
+		}
+	}
+	for _, c := range before {
+		if !inAfter[c] {
+			removed = append(removed, c)
+		}
+	}
+	sort.Strings(removed)
+	sort.Strings(added)
+	return removed, added
+}
@@ -2,109 +2,482 @@ package monitor
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/loiht2/ml-platform-training-job/backend/config"
+	"github.com/loiht2/ml-platform-training-job/backend/converter"
+	"github.com/loiht2/ml-platform-training-job/backend/jobstatus"
 	"github.com/loiht2/ml-platform-training-job/backend/karmada"
+	"github.com/loiht2/ml-platform-training-job/backend/models"
 	"github.com/loiht2/ml-platform-training-job/backend/repository"
 )
 
-// JobMonitor monitors job status in Karmada and updates database
+// statusLost marks a job whose status could not be read too many times in a row.
+// It is not terminal: the job is still retried and recovers once it is readable.
+const statusLost = "Lost"
+
+// retryBaseDelay is the first retry delay of a job whose status could not be read;
+// it doubles with every further failure
+const retryBaseDelay = time.Second
+
+// JobMonitor keeps the status of jobs in the database in line with Karmada.
+// It reconciles a job when a watch reports a change of its RayJob binding or
+// of the clusters it runs on, and every job on a slow periodic resync.
+// Federations whose client cannot watch are polled instead. Jobs are
+// reconciled by a fixed number of workers; jobs whose status cannot be read
+// are retried with exponential backoff. It also finishes deleting the jobs
+// whose resources outlived the delete request.
 type JobMonitor struct {
-	repo          *repository.Repository
-	karmadaClient *karmada.Client
-	stopChan      chan struct{}
-	wg            sync.WaitGroup
+	repo        repository.Store
+	federations *karmada.Federations
+	settings    config.MonitorSettings
+	// queue holds the jobKeys of the jobs to reconcile
+	queue    workqueue.RateLimitingInterface
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	stats    statsRecorder
+
+	mu sync.Mutex
+	// clusterReady is the last seen readiness of each member cluster by federation
+	clusterReady map[string]map[string]bool
+	// watched are the federations reporting changes through Watch
+	watched     map[string]bool
+	stopWatches []func()
+}
+
+// jobKey identifies a job by its federation and the RayJob it runs as
+type jobKey struct {
+	federation string
+	namespace  string
+	name       string
 }
 
-// NewJobMonitor creates a new job monitor
-func NewJobMonitor(repo *repository.Repository, karmadaClient *karmada.Client) *JobMonitor {
+// NewJobMonitor creates a new job monitor. Unset settings keep their defaults.
+func NewJobMonitor(repo repository.Store, federations *karmada.Federations, settings config.MonitorSettings) *JobMonitor {
+	settings = (&config.Settings{Monitor: &settings}).MonitorFor()
+	rateLimiter := workqueue.NewItemExponentialFailureRateLimiter(retryBaseDelay, time.Duration(settings.MaxBackoffSeconds)*time.Second)
 	return &JobMonitor{
-		repo:          repo,
-		karmadaClient: karmadaClient,
-		stopChan:      make(chan struct{}),
+		repo:         repo,
+		federations:  federations,
+		settings:     settings,
+		queue:        workqueue.NewRateLimitingQueue(rateLimiter),
+		stats:        statsRecorder{failing: map[jobKey]int{}},
+		stopChan:     make(chan struct{}),
+		clusterReady: map[string]map[string]bool{},
+		watched:      map[string]bool{},
 	}
 }
 
-// Start begins monitoring job status every 1 second
+// Start watches the federations and begins reconciling jobs
 func (m *JobMonitor) Start() {
-	m.wg.Add(1)
-	go m.monitorLoop()
-	log.Println("Job monitor started - polling every 1 second")
+	m.watch()
+	watched := m.watchedFederations()
+	for _, federation := range m.federations.Names() {
+		if !watched[federation] {
+			log.Printf("Federation %s cannot be watched yet; polling its jobs every %s", federation, m.pollPeriod())
+		}
+	}
+	m.resync(true)
+
+	m.wg.Add(m.settings.Workers + 2)
+	for i := 0; i < m.settings.Workers; i++ {
+		go m.worker()
+	}
+	go m.resyncLoop()
+	go m.deletionLoop()
+	log.Printf("Job monitor started - %d workers, watching %d of %d federations, resync every %s",
+		m.settings.Workers, len(m.watchedFederations()), len(m.federations.Names()), m.resyncPeriod())
 }
 
-// Stop stops the job monitor gracefully
+// Stop stops the job monitor gracefully. It may be called more than once.
 func (m *JobMonitor) Stop() {
+	select {
+	case <-m.stopChan:
+		return
+	default:
+	}
 	close(m.stopChan)
+
+	m.mu.Lock()
+	for _, stop := range m.stopWatches {
+		stop()
+	}
+	m.stopWatches = nil
+	m.mu.Unlock()
+
+	m.queue.ShutDown()
 	m.wg.Wait()
 	log.Println("Job monitor stopped")
 }
 
-// monitorLoop continuously monitors all jobs
-func (m *JobMonitor) monitorLoop() {
+// watch subscribes to the changes of every federation that can report them
+// and is not watched yet; federations whose inventory syncs late are picked
+// up on a later call. It returns the federations it began to watch.
+func (m *JobMonitor) watch() []string {
+	watched := m.watchedFederations()
+	var started []string
+	for _, federation := range m.federations.Names() {
+		federation := federation
+		if watched[federation] {
+			continue
+		}
+		karmadaClient, err := m.federations.Get(federation)
+		if err != nil {
+			continue
+		}
+		stop, ok := karmadaClient.Watch(karmada.WatchHandler{
+			RayJobChanged: func(namespace, name string) {
+				m.queue.Add(jobKey{federation: federation, namespace: namespace, name: name})
+			},
+			ClusterReadinessChanged: func(change karmada.ClusterReadinessChange) {
+				m.clusterReadinessChanged(federation, change)
+			},
+		})
+		if !ok {
+			continue
+		}
+
+		m.mu.Lock()
+		select {
+		case <-m.stopChan:
+			// Stopped meanwhile; Stop has already ended the other watches
+			m.mu.Unlock()
+			stop()
+			return started
+		default:
+		}
+		m.watched[federation] = true
+		m.stopWatches = append(m.stopWatches, stop)
+		m.mu.Unlock()
+		started = append(started, federation)
+	}
+	return started
+}
+
+// watchedFederations returns the federations reporting changes through Watch
+func (m *JobMonitor) watchedFederations() map[string]bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	watched := make(map[string]bool, len(m.watched))
+	for federation := range m.watched {
+		watched[federation] = true
+	}
+	return watched
+}
+
+// worker reconciles queued jobs until the queue shuts down
+func (m *JobMonitor) worker() {
+	defer m.wg.Done()
+	for m.processNextItem() {
+	}
+}
+
+// processNextItem reconciles the next queued job. It returns false once the queue shut down.
+func (m *JobMonitor) processNextItem() bool {
+	item, shutdown := m.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer m.queue.Done(item)
+
+	key := item.(jobKey)
+	start := time.Now()
+	err := m.reconcile(key)
+	m.stats.reconciled(time.Since(start))
+	if err == nil {
+		m.queue.Forget(item)
+		m.stats.succeeded(key)
+		return true
+	}
+
+	// Retry with backoff; resyncs leave it alone until then
+	failures := m.queue.NumRequeues(item) + 1
+	m.stats.failed(key, failures)
+	log.Printf("Failed to get status of job %s/%s (%d failures in a row): %v", key.namespace, key.name, failures, err)
+	if failures == m.settings.LostAfterFailures {
+		m.markLost(key, failures, err)
+	}
+	m.queue.AddRateLimited(item)
+	return true
+}
+
+// resyncPeriod is how often every active job is reconciled
+func (m *JobMonitor) resyncPeriod() time.Duration {
+	return time.Duration(m.settings.ResyncSeconds) * time.Second
+}
+
+// pollPeriod is how often the jobs of federations without a watch are reconciled
+func (m *JobMonitor) pollPeriod() time.Duration {
+	return time.Duration(m.settings.PollSeconds) * time.Second
+}
+
+// resyncLoop polls the federations without a watch, watching them once they
+// can be, and resyncs all of them now and then
+func (m *JobMonitor) resyncLoop() {
 	defer m.wg.Done()
 
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
+	poll := time.NewTicker(m.pollPeriod())
+	defer poll.Stop()
+	resync := time.NewTicker(m.resyncPeriod())
+	defer resync.Stop()
 
 	for {
 		select {
 		case <-m.stopChan:
 			return
-		case <-ticker.C:
-			m.checkAllJobs()
+		case <-poll.C:
+			for _, federation := range m.watch() {
+				log.Printf("Federation %s can be watched now; no longer polling its jobs", federation)
+			}
+			m.resync(false)
+		case <-resync.C:
+			m.resync(true)
 		}
 	}
 }
 
-// checkAllJobs checks status of all active jobs efficiently
-func (m *JobMonitor) checkAllJobs() {
+// resync queues every active job of the federations without a watch, or of
+// all federations, after recording the cluster readiness changes they missed.
+// Jobs waiting to be retried after a failure are left to their backoff.
+func (m *JobMonitor) resync(all bool) {
+	start := time.Now()
+	defer func() { m.stats.resynced(time.Since(start)) }()
+
+	watched := m.watchedFederations()
+	clusterChanges := map[string]map[string]bool{}
+	for _, federation := range m.federations.Names() {
+		if all || !watched[federation] {
+			clusterChanges[federation] = m.clusterReadinessChanges(federation)
+		}
+	}
+	if len(clusterChanges) == 0 {
+		return
+	}
+
 	// Get all jobs that are not in terminal state
 	jobs, err := m.repo.ListActiveJobs()
 	if err != nil {
 		log.Printf("Failed to list active jobs: %v", err)
 		return
 	}
-
 	if len(jobs) == 0 {
 		return
 	}
+	if all {
+		log.Printf("Resyncing %d active jobs", len(jobs))
+	}
+
+	for i := range jobs {
+		federation := m.jobFederation(&jobs[i])
+		changes, ok := clusterChanges[federation]
+		if !ok {
+			continue
+		}
+		m.recordClusterReadiness(&jobs[i], changes)
+		key := jobKey{federation: federation, namespace: jobs[i].Namespace, name: jobs[i].JobName}
+		if m.queue.NumRequeues(key) > 0 {
+			continue
+		}
+		m.queue.Add(key)
+	}
+}
+
+// reconcile refreshes the placement and status of the active jobs running as
+// a RayJob. It fails when the status of a job could not be read.
+func (m *JobMonitor) reconcile(key jobKey) error {
+	karmadaClient, err := m.federations.Get(key.federation)
+	if err != nil {
+		log.Printf("Skipping jobs %s/%s: %v", key.namespace, key.name, err)
+		return nil
+	}
+	jobs, err := m.repo.ListActiveJobsByName(key.namespace, key.name)
+	if err != nil {
+		return fmt.Errorf("failed to list active jobs: %w", err)
+	}
 
-	// Log periodically (reduce noise)
-	log.Printf("Monitoring %d active jobs", len(jobs))
+	var statusErr error
+	for i := range jobs {
+		if m.jobFederation(&jobs[i]) != key.federation {
+			continue
+		}
+		m.refreshPlacement(karmadaClient, &jobs[i])
+		if err := m.checkJobStatus(karmadaClient, &jobs[i]); err != nil {
+			statusErr = err
+		}
+	}
+	return statusErr
+}
 
-	// Process jobs sequentially but efficiently
-	// Note: Could be optimized with goroutines and semaphore if needed
-	for _, job := range jobs {
-		m.checkJobStatus(job.ID, job.JobName, job.Namespace)
+// markLost sets the status of the active jobs of key to Lost after their
+// status could not be read failures times in a row
+func (m *JobMonitor) markLost(key jobKey, failures int, cause error) {
+	jobs, err := m.repo.ListActiveJobsByName(key.namespace, key.name)
+	if err != nil {
+		log.Printf("Failed to list active jobs named %s/%s: %v", key.namespace, key.name, err)
+		return
+	}
+	message := fmt.Sprintf("Status could not be read %d times in a row: %v", failures, cause)
+	for i := range jobs {
+		if m.jobFederation(&jobs[i]) != key.federation || jobs[i].Status == statusLost {
+			continue
+		}
+		log.Printf("Job %s status changed: %s -> %s", jobs[i].ID, jobs[i].Status, statusLost)
+		if err := m.repo.UpdateTrainingJobStatus(jobs[i].ID, statusLost, message); err != nil {
+			log.Printf("Failed to update job status: %v", err)
+		}
 	}
 }
 
-// checkJobStatus checks the status of a single job
-func (m *JobMonitor) checkJobStatus(jobID, jobName, namespace string) {
+// jobFederation returns the federation a job runs in
+func (m *JobMonitor) jobFederation(job *config.TrainingJob) string {
+	if job.Federation == "" {
+		return m.federations.Default()
+	}
+	return job.Federation
+}
+
+// refreshPlacement records where Karmada scheduled a job when it changed
+func (m *JobMonitor) refreshPlacement(karmadaClient karmada.Interface, job *config.TrainingJob) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	// Get status from Karmada through aggregated API
-	// First, try to get RayJob status (most common)
-	rayJobStatus, err := m.karmadaClient.GetRayJobStatusFromMembers(ctx, jobName, namespace)
+	placement, err := karmadaClient.GetRayJobPlacement(ctx, job.JobName, job.Namespace)
+	if err != nil {
+		// Not scheduled yet, or not a RayJob
+		return
+	}
+
+	placementJSON, err := json.Marshal(placement)
+	if err != nil || string(placementJSON) == job.Placement {
+		return
+	}
+	if err := m.repo.UpdatePlacement(job.ID, placement); err != nil {
+		log.Printf("Failed to update placement for job %s: %v", job.ID, err)
+		return
+	}
+	m.recordReschedule(job, placement)
+	log.Printf("Job %s placement changed: %s", job.ID, placementJSON)
+}
+
+// checkJobStatus checks the status of a single job. A RayJob Karmada has not
+// scheduled yet is Pending, and so is a job whose PVC is not usable yet in its
+// clusters. It fails when neither the RayJob nor a Job of that name could be read.
+func (m *JobMonitor) checkJobStatus(karmadaClient karmada.Interface, job *config.TrainingJob) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Get the RayJob status from every cluster the job is placed on
+	clusters, err := jobstatus.CollectClusterStatuses(ctx, karmadaClient, job.JobName, job.Namespace)
 	if err != nil {
 		// If RayJob not found, try regular Job
-		k8sJob, err := m.karmadaClient.GetJobStatus(ctx, jobName, namespace)
-		if err != nil {
-			log.Printf("Failed to get status for job %s: %v", jobName, err)
-			return
+		k8sJob, jobErr := karmadaClient.GetJobStatus(ctx, job.JobName, job.Namespace)
+		var notScheduled *karmada.NotScheduledError
+		if jobErr != nil && errors.As(err, &notScheduled) {
+			message := "Waiting for Karmada to schedule the RayJob"
+			if notScheduled.Message != "" {
+				message = fmt.Sprintf("%s: %s", message, notScheduled.Message)
+			}
+			m.updatePendingJob(job.ID, message)
+			return nil
+		}
+		if jobErr != nil {
+			return jobErr
 		}
 
 		// Update status based on K8s Job
-		m.updateJobStatusFromK8sJobTyped(jobID, k8sJob)
-		return
+		m.updateJobStatusFromK8sJobTyped(job.ID, k8sJob)
+		return nil
+	}
+
+	phase, message, admission := jobstatus.AggregateClusterStatuses(clusters)
+	if phase == "" {
+		return fmt.Errorf("RayJob could not be read from any of its %d clusters", len(clusters))
+	}
+
+	// A job that has not started yet only does so once its PVC can be used
+	if (job.Status == "Pending" || job.Status == "Queued") && (phase == "Pending" || phase == "Running") {
+		claimPhase, claimMessage, err := m.claimStatus(ctx, karmadaClient, job, clusters)
+		if err != nil {
+			return err
+		}
+		switch claimPhase {
+		case "Pending":
+			m.updatePendingJob(job.ID, claimMessage)
+			return nil
+		case "Failed":
+			phase, message = claimPhase, claimMessage
+		}
 	}
 
 	// Update status based on RayJob
-	m.updateJobStatusFromRayJob(jobID, rayJobStatus)
+	m.updateJobStatusFromRayJob(job.ID, phase, message, admission)
+	return nil
+}
+
+// claimStatus checks the PVC a job owns in the clusters its RayJob is placed
+// on. It reports Failed when the claim is Lost or did not bind in time, and
+// Pending while it is not usable yet. It reports "" once the claim is Bound
+// everywhere, or is being provisioned for the scheduled pods of a
+// WaitForFirstConsumer storage class. Such claims wait for their pods without
+// a timeout: pods waiting for capacity are not a storage problem.
+func (m *JobMonitor) claimStatus(ctx context.Context, karmadaClient karmada.Interface, job *config.TrainingJob, clusters []models.ClusterJobStatus) (phase, message string, err error) {
+	resources, err := repository.JobResources(job)
+	if err != nil {
+		return "", "", err
+	}
+	timeout := time.Duration(m.settings.ClaimBindTimeoutSeconds) * time.Second
+
+	for _, ref := range resources {
+		if ref.Kind != "PersistentVolumeClaim" {
+			continue
+		}
+		for _, cluster := range clusters {
+			claim, err := karmadaClient.GetClaimStatus(ctx, cluster.Cluster, ref.Namespace, ref.Name)
+			if err != nil {
+				return "", "", err
+			}
+			switch {
+			case claim.Phase == string(corev1.ClaimBound):
+			case claim.Phase == string(corev1.ClaimLost):
+				return "Failed", fmt.Sprintf("PVC %s is Lost in cluster %s", ref.Name, cluster.Cluster), nil
+			case claim.WaitForFirstConsumer && claim.PodScheduled:
+			case claim.WaitForFirstConsumer:
+				return "Pending", fmt.Sprintf("PVC %s in cluster %s waits for the job's pods to be scheduled", ref.Name, cluster.Cluster), nil
+			case time.Since(job.CreatedAt) > timeout:
+				return "Failed", fmt.Sprintf("PVC %s did not bind in cluster %s within %s", ref.Name, cluster.Cluster, timeout), nil
+			default:
+				return "Pending", fmt.Sprintf("Waiting for PVC %s to bind in cluster %s", ref.Name, cluster.Cluster), nil
+			}
+		}
+	}
+	return "", "", nil
+}
+
+// updatePendingJob keeps a job that cannot start yet Pending, with the reason
+// as its message
+func (m *JobMonitor) updatePendingJob(jobID, message string) {
+	currentJob, err := m.repo.GetTrainingJob(jobID)
+	if err != nil {
+		log.Printf("Failed to get current job status: %v", err)
+		return
+	}
+	if currentJob.Status == "Deleting" || (currentJob.Status == "Pending" && currentJob.Message == message) {
+		return
+	}
+	if currentJob.Status != "Pending" {
+		log.Printf("Job %s status changed: %s -> Pending", jobID, currentJob.Status)
+	}
+	if err := m.repo.UpdateTrainingJobStatus(jobID, "Pending", message); err != nil {
+		log.Printf("Failed to update job status: %v", err)
+	}
 }
 
 // updateJobStatusFromK8sJobTyped updates database from K8s Job status (typed)
@@ -152,37 +525,8 @@ func (m *JobMonitor) updateJobStatusFromK8sJob(jobID string, status map[string]i
 	}
 }
 
-// updateJobStatusFromRayJob updates database from RayJob status
-func (m *JobMonitor) updateJobStatusFromRayJob(jobID string, status map[string]interface{}) {
-	// RayJob status has jobStatus and jobDeploymentStatus
-	jobStatus := getString(status, "jobStatus")
-	jobDeploymentStatus := getString(status, "jobDeploymentStatus")
-
-	var newStatus, message string
-
-	switch jobStatus {
-	case "SUCCEEDED":
-		newStatus = "Succeeded"
-		message = "RayJob completed successfully"
-	case "FAILED":
-		newStatus = "Failed"
-		message = "RayJob failed"
-	case "RUNNING":
-		newStatus = "Running"
-		message = fmt.Sprintf("RayJob is running (deployment: %s)", jobDeploymentStatus)
-	case "PENDING":
-		newStatus = "Pending"
-		message = "RayJob is pending"
-	default:
-		if jobDeploymentStatus == "Running" {
-			newStatus = "Running"
-			message = "RayJob cluster is running"
-		} else {
-			newStatus = "Pending"
-			message = fmt.Sprintf("RayJob deployment status: %s", jobDeploymentStatus)
-		}
-	}
-
+// updateJobStatusFromRayJob updates database from the aggregated RayJob status and queue admission state
+func (m *JobMonitor) updateJobStatusFromRayJob(jobID, newStatus, message, admission string) {
 	// Check if status changed
 	currentJob, err := m.repo.GetTrainingJob(jobID)
 	if err != nil {
@@ -190,15 +534,56 @@ func (m *JobMonitor) updateJobStatusFromRayJob(jobID string, status map[string]i
 		return
 	}
 
+	// Deleted while it was being checked
+	if currentJob.Status == "Deleting" {
+		return
+	}
+
+	if admission != "" && currentJob.Admission != admission {
+		log.Printf("Job %s admission changed: %q -> %s", jobID, currentJob.Admission, admission)
+		if err := m.repo.UpdateAdmissionState(jobID, admission); err != nil {
+			log.Printf("Failed to update job admission state: %v", err)
+		}
+	}
+
 	if currentJob.Status != newStatus {
 		log.Printf("Job %s status changed: %s -> %s", jobID, currentJob.Status, newStatus)
 		if err := m.repo.UpdateTrainingJobStatus(jobID, newStatus, message); err != nil {
 			log.Printf("Failed to update job status: %v", err)
 		}
+		if newStatus == "Succeeded" {
+			m.recordArtifactLocation(currentJob)
+		}
 	}
 }
 
+// recordArtifactLocation stores where a succeeded job wrote its artifacts
+func (m *JobMonitor) recordArtifactLocation(job *config.TrainingJob) {
+	req, err := jobRequest(job)
+	if err != nil {
+		log.Printf("Failed to decode request payload of job %s: %v", job.ID, err)
+		return
+	}
+
+	location := converter.ArtifactLocation(req)
+	if err := m.repo.SetArtifactLocation(job.ID, location); err != nil {
+		log.Printf("Failed to record artifact location of job %s: %v", job.ID, err)
+		return
+	}
+	log.Printf("Job %s artifacts stored at %s", job.ID, location)
+}
+
 // Helper functions
+
+// jobRequest decodes the original request stored with a job
+func jobRequest(job *config.TrainingJob) (*models.TrainingJobRequest, error) {
+	var req models.TrainingJobRequest
+	if err := json.Unmarshal([]byte(job.RequestPayload), &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
 func getInt32(m map[string]interface{}, key string) int32 {
 	if v, ok := m[key]; ok {
 		switch val := v.(type) {
@@ -214,14 +599,3 @@ func getInt32(m map[string]interface{}, key string) int32 {
 	}
 	return 0
 }
-
-func getString(m map[string]interface{}, key string) string {
-	if v, ok := m[key]; ok {
-		if s, ok := v.(string); ok {
-			return s
-		}
-	}
-	return ""
-}
-
-
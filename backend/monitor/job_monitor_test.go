@@ -0,0 +1,476 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/loiht2/ml-platform-training-job/backend/config"
+	"github.com/loiht2/ml-platform-training-job/backend/karmada"
+	"github.com/loiht2/ml-platform-training-job/backend/karmada/fake"
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+	"github.com/loiht2/ml-platform-training-job/backend/repository"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// submitJob stores a job and creates its RayJob in the given clusters
+func submitJob(t *testing.T, store repository.Store, client *fake.Client, id string, clusters ...string) {
+	t.Helper()
+	req := &models.TrainingJobRequest{
+		JobName:          id,
+		Namespace:        "default",
+		Algorithm:        models.Algorithm{AlgorithmName: "xgboost"},
+		TargetClusters:   clusters,
+		OutputDataConfig: models.OutputDataConfig{ArtifactURI: "s3://models/" + id},
+	}
+	if _, err := store.CreateTrainingJob(req, id); err != nil {
+		t.Fatalf("failed to store job: %v", err)
+	}
+	rayJob := map[string]interface{}{
+		"apiVersion": "ray.io/v1",
+		"kind":       "RayJob",
+		"metadata":   map[string]interface{}{"name": id, "namespace": "default"},
+	}
+	if _, err := client.CreateRayJobWithPropagationPolicy(context.Background(), rayJob, karmada.PropagationOptions{TargetClusters: clusters}); err != nil {
+		t.Fatalf("failed to create RayJob: %v", err)
+	}
+}
+
+// federationsOf makes client the only, default federation
+func federationsOf(client karmada.Interface) *karmada.Federations {
+	return karmada.NewFederations("default", map[string]karmada.Interface{"default": client})
+}
+
+// reconcileAll queues every active job and reconciles the queue
+func reconcileAll(m *JobMonitor) {
+	m.resync(true)
+	drain(m)
+}
+
+// drain reconciles the queued jobs
+func drain(m *JobMonitor) {
+	for m.queue.Len() > 0 {
+		m.processNextItem()
+	}
+}
+
+func jobStatus(t *testing.T, store repository.Store, id string) string {
+	t.Helper()
+	job, err := store.GetTrainingJob(id)
+	if err != nil {
+		t.Fatalf("failed to get job %s: %v", id, err)
+	}
+	return job.Status
+}
+
+func TestResyncFollowsRayJobLifecycle(t *testing.T) {
+	store := repository.NewMemoryStore()
+	client := fake.NewClient(models.ClusterInfo{Name: "member-1", Ready: true})
+	m := NewJobMonitor(store, federationsOf(client), config.MonitorSettings{})
+	submitJob(t, store, client, "job-1", "member-1")
+
+	for _, want := range []string{"Pending", "Pending", "Running", "Succeeded"} {
+		reconcileAll(m)
+		if got := jobStatus(t, store, "job-1"); got != want {
+			t.Fatalf("got status %q, want %q", got, want)
+		}
+		client.Step("default", "job-1")
+	}
+
+	job, _ := store.GetTrainingJob("job-1")
+	if job.ArtifactURI == "" {
+		t.Errorf("artifact location was not recorded")
+	}
+	var placement []models.ClusterPlacement
+	if err := json.Unmarshal([]byte(job.Placement), &placement); err != nil || len(placement) != 1 || placement[0].Cluster != "member-1" {
+		t.Errorf("got placement %s, want member-1", job.Placement)
+	}
+
+	// Finished jobs are no longer checked
+	client.SetRayJobStatus("member-1", "default", "job-1", fake.DeploymentFailed, fake.JobFailed)
+	reconcileAll(m)
+	if got := jobStatus(t, store, "job-1"); got != "Succeeded" {
+		t.Errorf("finished job changed to %q", got)
+	}
+}
+
+func TestResyncAggregatesClusters(t *testing.T) {
+	store := repository.NewMemoryStore()
+	client := fake.NewClient(
+		models.ClusterInfo{Name: "member-1", Ready: true},
+		models.ClusterInfo{Name: "member-2", Ready: true},
+	)
+	m := NewJobMonitor(store, federationsOf(client), config.MonitorSettings{})
+	submitJob(t, store, client, "job-1", "member-1", "member-2")
+
+	client.SetRayJobStatus("member-1", "default", "job-1", fake.DeploymentComplete, fake.JobSucceeded)
+	client.SetRayJobStatus("member-2", "default", "job-1", fake.DeploymentRunning, fake.JobRunning)
+	reconcileAll(m)
+	if got := jobStatus(t, store, "job-1"); got != "Running" {
+		t.Errorf("one cluster done: got status %q, want Running", got)
+	}
+
+	client.SetRayJobStatus("member-2", "default", "job-1", fake.DeploymentFailed, fake.JobFailed)
+	reconcileAll(m)
+	if got := jobStatus(t, store, "job-1"); got != "Failed" {
+		t.Errorf("one cluster failed: got status %q, want Failed", got)
+	}
+}
+
+func TestResyncRecordsClusterFailures(t *testing.T) {
+	store := repository.NewMemoryStore()
+	client := fake.NewClient(
+		models.ClusterInfo{Name: "member-1", Ready: true},
+		models.ClusterInfo{Name: "member-2", Ready: true},
+	)
+	m := NewJobMonitor(store, federationsOf(client), config.MonitorSettings{})
+	submitJob(t, store, client, "job-1", "member-1", "member-2")
+	client.Step("default", "job-1")
+	client.Step("default", "job-1")
+	reconcileAll(m)
+
+	client.SetClusterReady("member-2", false)
+	reconcileAll(m)
+
+	job, _ := store.GetTrainingJob("job-1")
+	var events []models.FailoverEvent
+	if err := json.Unmarshal([]byte(job.FailoverEvents), &events); err != nil {
+		t.Fatalf("failed to decode failover events %q: %v", job.FailoverEvents, err)
+	}
+	if len(events) != 1 || events[0].Reason != models.FailoverClusterNotReady || events[0].FromClusters[0] != "member-2" {
+		t.Errorf("got failover events %+v, want member-2 NotReady", events)
+	}
+
+	// The unreadable cluster does not hold back the one still running
+	if got := jobStatus(t, store, "job-1"); got != "Running" {
+		t.Errorf("got status %q, want Running", got)
+	}
+}
+
+func TestResyncSkipsDeletingJobs(t *testing.T) {
+	store := repository.NewMemoryStore()
+	client := fake.NewClient(models.ClusterInfo{Name: "member-1", Ready: true})
+	m := NewJobMonitor(store, federationsOf(client), config.MonitorSettings{})
+	submitJob(t, store, client, "job-1", "member-1")
+	store.UpdateTrainingJobStatus("job-1", "Deleting", "Deleting job resources")
+
+	client.SetRayJobStatus("member-1", "default", "job-1", fake.DeploymentRunning, fake.JobRunning)
+	reconcileAll(m)
+	if got := jobStatus(t, store, "job-1"); got != "Deleting" {
+		t.Errorf("got status %q, want Deleting", got)
+	}
+}
+
+func TestResyncUsesJobFederation(t *testing.T) {
+	store := repository.NewMemoryStore()
+	prod := fake.NewClient(models.ClusterInfo{Name: "member-1", Ready: true})
+	research := fake.NewClient(models.ClusterInfo{Name: "lab-1", Ready: true})
+	m := NewJobMonitor(store, karmada.NewFederations("prod", map[string]karmada.Interface{"prod": prod, "research": research}), config.MonitorSettings{})
+
+	submitJob(t, store, prod, "prod-job", "member-1")
+	req := &models.TrainingJobRequest{JobName: "research-job", Namespace: "default", Federation: "research"}
+	if _, err := store.CreateTrainingJob(req, "research-job"); err != nil {
+		t.Fatalf("failed to store job: %v", err)
+	}
+	rayJob := map[string]interface{}{"metadata": map[string]interface{}{"name": "research-job", "namespace": "default"}}
+	if _, err := research.CreateRayJobWithPropagationPolicy(context.Background(), rayJob, karmada.PropagationOptions{}); err != nil {
+		t.Fatalf("failed to create RayJob: %v", err)
+	}
+
+	research.SetRayJobStatus("lab-1", "default", "research-job", fake.DeploymentRunning, fake.JobRunning)
+	reconcileAll(m)
+	if got := jobStatus(t, store, "research-job"); got != "Running" {
+		t.Errorf("research job: got status %q, want Running", got)
+	}
+	if got := jobStatus(t, store, "prod-job"); got != "Pending" {
+		t.Errorf("prod job: got status %q, want Pending", got)
+	}
+}
+
+func TestWatchReconcilesChangedJobs(t *testing.T) {
+	store := repository.NewMemoryStore()
+	client := fake.NewClient(models.ClusterInfo{Name: "member-1", Ready: true})
+	m := NewJobMonitor(store, federationsOf(client), config.MonitorSettings{})
+	m.watch()
+	defer m.Stop()
+
+	submitJob(t, store, client, "job-1", "member-1")
+	submitJob(t, store, client, "job-2", "member-1")
+	drain(m)
+
+	// Only the job whose RayJob changed is reconciled
+	client.Step("default", "job-1")
+	client.Step("default", "job-1")
+	if got := m.queue.Len(); got != 1 {
+		t.Fatalf("got %d queued jobs, want 1", got)
+	}
+	drain(m)
+	if got := jobStatus(t, store, "job-1"); got != "Running" {
+		t.Errorf("job-1: got status %q, want Running", got)
+	}
+	if got := jobStatus(t, store, "job-2"); got != "Pending" {
+		t.Errorf("job-2: got status %q, want Pending", got)
+	}
+
+	client.Step("default", "job-1")
+	drain(m)
+	if got := jobStatus(t, store, "job-1"); got != "Succeeded" {
+		t.Errorf("job-1: got status %q, want Succeeded", got)
+	}
+}
+
+func TestWatchRecordsClusterFailures(t *testing.T) {
+	store := repository.NewMemoryStore()
+	client := fake.NewClient(
+		models.ClusterInfo{Name: "member-1", Ready: true},
+		models.ClusterInfo{Name: "member-2", Ready: true},
+	)
+	m := NewJobMonitor(store, federationsOf(client), config.MonitorSettings{})
+	m.watch()
+	defer m.Stop()
+	submitJob(t, store, client, "job-1", "member-1")
+	submitJob(t, store, client, "job-2", "member-2")
+	reconcileAll(m)
+
+	client.SetClusterReady("member-2", false)
+	if got := m.queue.Len(); got != 1 {
+		t.Errorf("got %d queued jobs, want the one on member-2", got)
+	}
+
+	for id, want := range map[string]int{"job-1": 0, "job-2": 1} {
+		job, _ := store.GetTrainingJob(id)
+		var events []models.FailoverEvent
+		if job.FailoverEvents != "" {
+			if err := json.Unmarshal([]byte(job.FailoverEvents), &events); err != nil {
+				t.Fatalf("failed to decode failover events %q: %v", job.FailoverEvents, err)
+			}
+		}
+		if len(events) != want {
+			t.Errorf("%s: got failover events %+v, want %d", id, events, want)
+		}
+	}
+
+	// The resync does not record the change again
+	reconcileAll(m)
+	job, _ := store.GetTrainingJob("job-2")
+	var events []models.FailoverEvent
+	if err := json.Unmarshal([]byte(job.FailoverEvents), &events); err != nil {
+		t.Fatalf("failed to decode failover events %q: %v", job.FailoverEvents, err)
+	}
+	if len(events) != 1 {
+		t.Errorf("got failover events %+v after resync, want 1", events)
+	}
+}
+
+func TestWatchPicksUpFederationOnceItCanBeWatched(t *testing.T) {
+	store := repository.NewMemoryStore()
+	client := fake.NewClient(models.ClusterInfo{Name: "member-1", Ready: true})
+	client.SetWatchable(false)
+	m := NewJobMonitor(store, federationsOf(client), config.MonitorSettings{})
+	if started := m.watch(); len(started) != 0 {
+		t.Fatalf("watched %v before the inventory synced", started)
+	}
+	defer m.Stop()
+	submitJob(t, store, client, "job-1", "member-1")
+	drain(m)
+
+	client.Step("default", "job-1")
+	if got := m.queue.Len(); got != 0 {
+		t.Fatalf("got %d queued jobs without a watch, want 0", got)
+	}
+
+	// The inventory synced in the background
+	client.SetWatchable(true)
+	if started := m.watch(); !reflect.DeepEqual(started, []string{"default"}) {
+		t.Fatalf("started watching %v, want default", started)
+	}
+	if started := m.watch(); len(started) != 0 {
+		t.Errorf("watched %v again", started)
+	}
+	client.Step("default", "job-1")
+	if got := m.queue.Len(); got != 1 {
+		t.Errorf("got %d queued jobs, want the changed one", got)
+	}
+	if stats := m.Stats(); len(stats.PolledFederations) != 0 {
+		t.Errorf("polled federations = %v, want none once watched", stats.PolledFederations)
+	}
+}
+
+func TestUnscheduledJobStaysPending(t *testing.T) {
+	store := repository.NewMemoryStore()
+	client := fake.NewClient(models.ClusterInfo{Name: "member-1", Ready: true})
+	m := NewJobMonitor(store, federationsOf(client), config.MonitorSettings{LostAfterFailures: 2})
+	// No member cluster matches, so the binding targets no cluster
+	submitJob(t, store, client, "job-1", "member-9")
+	key := jobKey{federation: "default", namespace: "default", name: "job-1"}
+
+	for i := 0; i < 3; i++ {
+		m.queue.Add(key)
+		drain(m)
+	}
+	job, _ := store.GetTrainingJob("job-1")
+	if job.Status != "Pending" || !strings.Contains(job.Message, fake.UnschedulableMessage) {
+		t.Errorf("got status %q (%s), want Pending with the scheduling message", job.Status, job.Message)
+	}
+	if stats := m.Stats(); stats.Retrying != 0 || stats.ReconcileFailures != 0 || m.queue.NumRequeues(key) != 0 {
+		t.Errorf("stats = %+v, want the unscheduled job not counted as failing", stats)
+	}
+}
+
+func TestJobStartsOnceItsClaimIsUsable(t *testing.T) {
+	tests := []struct {
+		name        string
+		claim       karmada.ClaimStatus
+		want        string
+		wantMessage string
+	}{
+		{"bound", karmada.ClaimStatus{Phase: "Bound"}, "Running", ""},
+		{"provisioned for scheduled pods", karmada.ClaimStatus{Phase: "Pending", WaitForFirstConsumer: true, PodScheduled: true}, "Running", ""},
+		{"waiting for pods", karmada.ClaimStatus{Phase: "Pending", WaitForFirstConsumer: true}, "Pending", "waits for the job's pods"},
+		{"not bound", karmada.ClaimStatus{Phase: "Pending"}, "Pending", "Waiting for PVC job-1-pvc to bind in cluster member-1"},
+		{"not propagated", karmada.ClaimStatus{}, "Pending", "Waiting for PVC job-1-pvc to bind"},
+		{"lost", karmada.ClaimStatus{Phase: "Lost"}, "Failed", "PVC job-1-pvc is Lost in cluster member-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := repository.NewMemoryStore()
+			client := fake.NewClient(models.ClusterInfo{Name: "member-1", Ready: true})
+			m := NewJobMonitor(store, federationsOf(client), config.MonitorSettings{})
+			submitJob(t, store, client, "job-1", "member-1")
+			claim := models.ResourceRef{APIVersion: "v1", Kind: "PersistentVolumeClaim", Namespace: "default", Name: "job-1-pvc"}
+			if err := store.SetJobResources("job-1", []models.ResourceRef{claim}); err != nil {
+				t.Fatalf("failed to record job resources: %v", err)
+			}
+			client.SetClaimStatus("member-1", "default", "job-1-pvc", tt.claim)
+			if err := client.SetRayJobStatus("member-1", "default", "job-1", fake.DeploymentRunning, fake.JobRunning); err != nil {
+				t.Fatalf("failed to set RayJob status: %v", err)
+			}
+
+			reconcileAll(m)
+			job, _ := store.GetTrainingJob("job-1")
+			if job.Status != tt.want || !strings.Contains(job.Message, tt.wantMessage) {
+				t.Errorf("got status %q (%s), want %s with %q", job.Status, job.Message, tt.want, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestJobFailsWhenItsClaimDoesNotBindInTime(t *testing.T) {
+	store := repository.NewMemoryStore()
+	client := fake.NewClient(models.ClusterInfo{Name: "member-1", Ready: true})
+	m := NewJobMonitor(store, federationsOf(client), config.MonitorSettings{ClaimBindTimeoutSeconds: 1})
+	submitJob(t, store, client, "job-1", "member-1")
+	claim := models.ResourceRef{APIVersion: "v1", Kind: "PersistentVolumeClaim", Namespace: "default", Name: "job-1-pvc"}
+	if err := store.SetJobResources("job-1", []models.ResourceRef{claim}); err != nil {
+		t.Fatalf("failed to record job resources: %v", err)
+	}
+	client.SetClaimStatus("member-1", "default", "job-1-pvc", karmada.ClaimStatus{Phase: "Pending"})
+
+	reconcileAll(m)
+	if got := jobStatus(t, store, "job-1"); got != "Pending" {
+		t.Fatalf("got status %q before the timeout, want Pending", got)
+	}
+
+	time.Sleep(1100 * time.Millisecond)
+	reconcileAll(m)
+	job, _ := store.GetTrainingJob("job-1")
+	if job.Status != "Failed" || !strings.Contains(job.Message, "did not bind") {
+		t.Errorf("got status %q (%s), want Failed for the unbound claim", job.Status, job.Message)
+	}
+}
+
+func TestFailingJobsBackOffAndGetLost(t *testing.T) {
+	store := repository.NewMemoryStore()
+	client := fake.NewClient(models.ClusterInfo{Name: "member-1", Ready: true})
+	m := NewJobMonitor(store, federationsOf(client), config.MonitorSettings{LostAfterFailures: 3})
+	submitJob(t, store, client, "job-1", "member-1")
+	key := jobKey{federation: "default", namespace: "default", name: "job-1"}
+
+	unreachable := errors.New("connection refused")
+	client.FailOn("GetRayJobsFromMembers", unreachable)
+	client.FailOn("GetJobStatus", unreachable)
+	reconcileAll(m)
+	if stats := m.Stats(); stats.Retrying != 1 || stats.ReconcileFailures != 1 {
+		t.Errorf("stats = %+v, want one retrying job", stats)
+	}
+
+	// The resync leaves the failing job to its backoff
+	m.resync(true)
+	if got := m.queue.Len(); got != 0 {
+		t.Errorf("got %d queued jobs after resync, want 0", got)
+	}
+
+	// Lost only after the configured number of failures in a row
+	for failures, want := range []string{"Pending", statusLost} {
+		m.queue.Add(key)
+		drain(m)
+		if got := jobStatus(t, store, "job-1"); got != want {
+			t.Errorf("after %d failures: got status %q, want %q", failures+2, got, want)
+		}
+	}
+
+	// Recovers once the status can be read again
+	client.FailOn("GetRayJobsFromMembers", nil)
+	client.FailOn("GetJobStatus", nil)
+	client.SetRayJobStatus("member-1", "default", "job-1", fake.DeploymentRunning, fake.JobRunning)
+	m.queue.Add(key)
+	drain(m)
+	if got := jobStatus(t, store, "job-1"); got != "Running" {
+		t.Errorf("after recovery: got status %q, want Running", got)
+	}
+	if stats := m.Stats(); stats.Retrying != 0 || m.queue.NumRequeues(key) != 0 {
+		t.Errorf("stats = %+v, want no retrying jobs", stats)
+	}
+}
+
+func TestDeletingJobIsRemovedOnceItsResourcesAreGone(t *testing.T) {
+	store := repository.NewMemoryStore()
+	client := fake.NewClient(models.ClusterInfo{Name: "member-1", Ready: true})
+	m := NewJobMonitor(store, federationsOf(client), config.MonitorSettings{})
+	submitJob(t, store, client, "job-1", "member-1")
+
+	resources := client.Objects()
+	if err := store.SetJobResources("job-1", resources); err != nil {
+		t.Fatalf("failed to record job resources: %v", err)
+	}
+	var rayJob models.ResourceRef
+	for _, ref := range resources {
+		if ref.Kind == "RayJob" {
+			rayJob = ref
+		}
+	}
+	client.SetLingering(rayJob, true)
+	if err := store.UpdateTrainingJobStatus("job-1", "Deleting", "Deleting job resources"); err != nil {
+		t.Fatalf("failed to mark job as deleting: %v", err)
+	}
+
+	m.finishDeletions()
+	job, err := store.GetTrainingJob("job-1")
+	if err != nil {
+		t.Fatalf("job with a RayJob still being removed was deleted: %v", err)
+	}
+	if job.Status != "Deleting" || !strings.Contains(job.Message, "1 still being removed") {
+		t.Errorf("got status %q (%s), want Deleting with the RayJob still being removed", job.Status, job.Message)
+	}
+
+	client.SetLingering(rayJob, false)
+	m.finishDeletions()
+	if _, err := store.GetTrainingJob("job-1"); err == nil {
+		t.Errorf("job was not deleted once its resources were gone")
+	}
+	if objects := client.Objects(); len(objects) != 0 {
+		t.Errorf("objects %v were left behind", objects)
+	}
+}
@@ -0,0 +1,94 @@
+package monitor
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// statsRecorder accumulates the durations and failures behind the monitor stats
+type statsRecorder struct {
+	mu                sync.Mutex
+	lastResync        time.Time
+	lastResyncTime    time.Duration
+	reconciles        int64
+	reconcileFailures int64
+	lastReconcileTime time.Duration
+	maxReconcileTime  time.Duration
+	totalReconcile    time.Duration
+	// failing counts the failures in a row of the jobs being retried
+	failing map[jobKey]int
+}
+
+func (r *statsRecorder) resynced(took time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastResync = time.Now()
+	r.lastResyncTime = took
+}
+
+func (r *statsRecorder) reconciled(took time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reconciles++
+	r.lastReconcileTime = took
+	r.totalReconcile += took
+	if took > r.maxReconcileTime {
+		r.maxReconcileTime = took
+	}
+}
+
+func (r *statsRecorder) succeeded(key jobKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.failing, key)
+}
+
+func (r *statsRecorder) failed(key jobKey, failures int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reconcileFailures++
+	r.failing[key] = failures
+}
+
+// Stats returns the current load of the job monitor
+func (m *JobMonitor) Stats() models.MonitorStats {
+	stats := models.MonitorStats{
+		Workers:            m.settings.Workers,
+		WatchedFederations: []string{},
+		PolledFederations:  []string{},
+		ResyncSeconds:      m.settings.ResyncSeconds,
+		PollSeconds:        m.settings.PollSeconds,
+		Backlog:            m.queue.Len(),
+	}
+	watched := m.watchedFederations()
+	for _, federation := range m.federations.Names() {
+		if watched[federation] {
+			stats.WatchedFederations = append(stats.WatchedFederations, federation)
+		} else {
+			stats.PolledFederations = append(stats.PolledFederations, federation)
+		}
+	}
+	sort.Strings(stats.WatchedFederations)
+	sort.Strings(stats.PolledFederations)
+
+	r := &m.stats
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats.Retrying = len(r.failing)
+	if !r.lastResync.IsZero() {
+		lastResync := r.lastResync
+		stats.LastResync = &lastResync
+	}
+	stats.LastResyncSeconds = r.lastResyncTime.Seconds()
+	stats.Reconciles = r.reconciles
+	stats.ReconcileFailures = r.reconcileFailures
+	stats.LastReconcileSeconds = r.lastReconcileTime.Seconds()
+	stats.MaxReconcileSeconds = r.maxReconcileTime.Seconds()
+	if r.reconciles > 0 {
+		stats.AvgReconcileSeconds = (r.totalReconcile / time.Duration(r.reconciles)).Seconds()
+	}
+	return stats
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/loiht2/ml-platform-training-job/backend/config"
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// MemoryStore keeps training jobs in memory. Missing jobs are reported with
+// gorm.ErrRecordNotFound, like Repository.
+type MemoryStore struct {
+	mu   sync.Mutex
+	jobs map[string]*config.TrainingJob
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{jobs: map[string]*config.TrainingJob{}}
+}
+
+// CreateTrainingJob creates a new training job record
+func (s *MemoryStore) CreateTrainingJob(req *models.TrainingJobRequest, id string) (*config.TrainingJob, error) {
+	job, err := newTrainingJob(req, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.jobs[id]; exists {
+		return nil, fmt.Errorf("failed to create training job: duplicate id %s", id)
+	}
+	stored := *job
+	s.jobs[id] = &stored
+	return job, nil
+}
+
+// GetTrainingJob retrieves a copy of a training job by ID
+func (s *MemoryStore) GetTrainingJob(id string) (*config.TrainingJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *job
+	return &copied, nil
+}
+
+// ListTrainingJobs lists the training jobs of a namespace, or all when namespace is empty, newest first
+func (s *MemoryStore) ListTrainingJobs(namespace string) ([]config.TrainingJob, error) {
+	return s.list(func(job *config.TrainingJob) bool {
+		return namespace == "" || job.Namespace == namespace
+	}), nil
+}
+
+// ListActiveJobs lists all jobs that are not in terminal state or being deleted, newest first
+func (s *MemoryStore) ListActiveJobs() ([]config.TrainingJob, error) {
+	return s.list(func(job *config.TrainingJob) bool {
+		switch job.Status {
+		case "Succeeded", "Failed", "Deleting":
+			return false
+		}
+		return true
+	}), nil
+}
+
+// ListActiveJobsByName lists the active jobs with the given name in a namespace, newest first
+func (s *MemoryStore) ListActiveJobsByName(namespace, jobName string) ([]config.TrainingJob, error) {
+	return s.list(func(job *config.TrainingJob) bool {
+		switch job.Status {
+		case "Succeeded", "Failed", "Deleting":
+			return false
+		}
+		return job.Namespace == namespace && job.JobName == jobName
+	}), nil
+}
+
+// ListDeletingJobs lists the jobs whose resources are still being deleted, newest first
+func (s *MemoryStore) ListDeletingJobs() ([]config.TrainingJob, error) {
+	return s.list(func(job *config.TrainingJob) bool {
+		return job.Status == "Deleting"
+	}), nil
+}
+
+// UpdateTrainingJobStatus updates the status of a training job
+func (s *MemoryStore) UpdateTrainingJobStatus(id, status, message string) error {
+	return s.update(id, func(job *config.TrainingJob) error {
+		job.Status = status
+		job.Message = message
+		return nil
+	})
+}
+
+// UpdateAdmissionState records whether a queued job has been admitted
+func (s *MemoryStore) UpdateAdmissionState(id, admission string) error {
+	return s.update(id, func(job *config.TrainingJob) error {
+		job.Admission = admission
+		return nil
+	})
+}
+
+// SetArtifactLocation records where a finished job stored its artifacts
+func (s *MemoryStore) SetArtifactLocation(id, location string) error {
+	return s.update(id, func(job *config.TrainingJob) error {
+		job.ArtifactURI = location
+		return nil
+	})
+}
+
+// UpdatePlacement records the clusters Karmada scheduled a job to
+func (s *MemoryStore) UpdatePlacement(id string, placement []models.ClusterPlacement) error {
+	placementJSON, err := json.Marshal(placement)
+	if err != nil {
+		return fmt.Errorf("failed to marshal placement: %w", err)
+	}
+	return s.update(id, func(job *config.TrainingJob) error {
+		job.Placement = string(placementJSON)
+		return nil
+	})
+}
+
+// AppendFailoverEvent adds an event to a job's failover history
+func (s *MemoryStore) AppendFailoverEvent(id string, event models.FailoverEvent) error {
+	return s.update(id, func(job *config.TrainingJob) error {
+		eventsJSON, err := appendFailoverEvent(job.FailoverEvents, event)
+		if err != nil {
+			return err
+		}
+		job.FailoverEvents = eventsJSON
+		return nil
+	})
+}
+
+// SetJobResources records the Kubernetes objects created for a job
+func (s *MemoryStore) SetJobResources(id string, resources []models.ResourceRef) error {
+	resourcesJSON, err := json.Marshal(resources)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job resources: %w", err)
+	}
+	return s.update(id, func(job *config.TrainingJob) error {
+		job.Resources = string(resourcesJSON)
+		return nil
+	})
+}
+
+// DeleteTrainingJob deletes a training job
+func (s *MemoryStore) DeleteTrainingJob(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, id)
+	return nil
+}
+
+// update applies a change to a stored job; like an SQL update, a missing job is not an error
+func (s *MemoryStore) update(id string, change func(job *config.TrainingJob) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil
+	}
+	if err := change(job); err != nil {
+		return err
+	}
+	job.UpdatedAt = time.Now()
+	return nil
+}
+
+func (s *MemoryStore) list(match func(job *config.TrainingJob) bool) []config.TrainingJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := []config.TrainingJob{}
+	for _, job := range s.jobs {
+		if match(job) {
+			jobs = append(jobs, *job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+	return jobs
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/loiht2/ml-platform-training-job/backend/config"
 	"github.com/loiht2/ml-platform-training-job/backend/models"
@@ -23,6 +24,20 @@ func NewRepository(db *gorm.DB) *Repository {
 
 // CreateTrainingJob creates a new training job record
 func (r *Repository) CreateTrainingJob(req *models.TrainingJobRequest, id string) (*config.TrainingJob, error) {
+	job, err := newTrainingJob(req, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.db.Create(job).Error; err != nil {
+		return nil, fmt.Errorf("failed to create training job: %w", err)
+	}
+
+	return job, nil
+}
+
+// newTrainingJob builds the record of a new training job
+func newTrainingJob(req *models.TrainingJobRequest, id string) (*config.TrainingJob, error) {
 	// Marshal entire request as JSON
 	requestJSON, err := json.Marshal(req)
 	if err != nil {
@@ -33,29 +48,25 @@ func (r *Repository) CreateTrainingJob(req *models.TrainingJobRequest, id string
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal target clusters: %w", err)
 	}
-	
+
 	namespace := req.Namespace
 	if namespace == "" {
 		namespace = "default"
 	}
 
-	job := &config.TrainingJob{
+	return &config.TrainingJob{
 		ID:             id,
 		JobName:        req.JobName,
 		Namespace:      namespace,
+		Federation:     req.Federation,
 		Algorithm:      req.Algorithm.AlgorithmName,
+		Priority:       req.Priority,
 		RequestPayload: string(requestJSON),
 		TargetClusters: string(targetClustersJSON),
 		Status:         "Pending",
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
-	}
-
-	if err := r.db.Create(job).Error; err != nil {
-		return nil, fmt.Errorf("failed to create training job: %w", err)
-	}
-
-	return job, nil
+	}, nil
 }
 
 // GetTrainingJob retrieves a training job by ID
@@ -71,11 +82,11 @@ func (r *Repository) GetTrainingJob(id string) (*config.TrainingJob, error) {
 func (r *Repository) ListTrainingJobs(namespace string) ([]config.TrainingJob, error) {
 	var jobs []config.TrainingJob
 	query := r.db.Order("created_at DESC")
-	
+
 	if namespace != "" {
 		query = query.Where("namespace = ?", namespace)
 	}
-	
+
 	if err := query.Find(&jobs).Error; err != nil {
 		return nil, err
 	}
@@ -93,13 +104,115 @@ func (r *Repository) UpdateTrainingJobStatus(id, status, message string) error {
 		}).Error
 }
 
+// UpdateAdmissionState records whether a queued job has been admitted
+func (r *Repository) UpdateAdmissionState(id, admission string) error {
+	return r.db.Model(&config.TrainingJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"admission":  admission,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// SetArtifactLocation records where a finished job stored its artifacts
+func (r *Repository) SetArtifactLocation(id, location string) error {
+	return r.db.Model(&config.TrainingJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"artifact_uri": location,
+			"updated_at":   time.Now(),
+		}).Error
+}
+
+// UpdatePlacement records the clusters Karmada scheduled a job to
+func (r *Repository) UpdatePlacement(id string, placement []models.ClusterPlacement) error {
+	placementJSON, err := json.Marshal(placement)
+	if err != nil {
+		return fmt.Errorf("failed to marshal placement: %w", err)
+	}
+
+	return r.db.Model(&config.TrainingJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"placement":  string(placementJSON),
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// AppendFailoverEvent adds an event to a job's failover history. The job row
+// is locked while the history is rewritten, so concurrent appends are kept.
+func (r *Repository) AppendFailoverEvent(id string, event models.FailoverEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var job config.TrainingJob
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&job).Error; err != nil {
+			return err
+		}
+
+		eventsJSON, err := appendFailoverEvent(job.FailoverEvents, event)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&config.TrainingJob{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"failover_events": eventsJSON,
+				"updated_at":      time.Now(),
+			}).Error
+	})
+}
+
+// appendFailoverEvent adds an event to the JSON failover history of a job
+func appendFailoverEvent(eventsJSON string, event models.FailoverEvent) (string, error) {
+	var events []models.FailoverEvent
+	if eventsJSON != "" {
+		if err := json.Unmarshal([]byte(eventsJSON), &events); err != nil {
+			return "", fmt.Errorf("failed to unmarshal failover events: %w", err)
+		}
+	}
+	events = append(events, event)
+
+	data, err := json.Marshal(events)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal failover events: %w", err)
+	}
+	return string(data), nil
+}
+
+// SetJobResources records the Kubernetes objects created for a job
+func (r *Repository) SetJobResources(id string, resources []models.ResourceRef) error {
+	resourcesJSON, err := json.Marshal(resources)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job resources: %w", err)
+	}
+
+	return r.db.Model(&config.TrainingJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"resources":  string(resourcesJSON),
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// JobResources returns the Kubernetes objects recorded for a job. Jobs
+// created before objects were recorded have none.
+func JobResources(job *config.TrainingJob) ([]models.ResourceRef, error) {
+	var resources []models.ResourceRef
+	if job.Resources != "" {
+		if err := json.Unmarshal([]byte(job.Resources), &resources); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal job resources: %w", err)
+		}
+	}
+	return resources, nil
+}
+
 // DeleteTrainingJob soft deletes a training job
 func (r *Repository) DeleteTrainingJob(id string) error {
 	return r.db.Where("id = ?", id).Delete(&config.TrainingJob{}).Error
 }
 
 // ToResponse converts a database TrainingJob to API response
-func (r *Repository) ToResponse(job *config.TrainingJob) (*models.TrainingJobResponse, error) {
+func ToResponse(job *config.TrainingJob) (*models.TrainingJobResponse, error) {
 	// Reconstruct the original request
 	var req models.TrainingJobRequest
 	if err := json.Unmarshal([]byte(job.RequestPayload), &req); err != nil {
@@ -111,23 +224,74 @@ func (r *Repository) ToResponse(job *config.TrainingJob) (*models.TrainingJobRes
 		return nil, fmt.Errorf("failed to unmarshal target clusters: %w", err)
 	}
 
+	var placement []models.ClusterPlacement
+	if job.Placement != "" {
+		if err := json.Unmarshal([]byte(job.Placement), &placement); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal placement: %w", err)
+		}
+	}
+
+	var failoverEvents []models.FailoverEvent
+	if job.FailoverEvents != "" {
+		if err := json.Unmarshal([]byte(job.FailoverEvents), &failoverEvents); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal failover events: %w", err)
+		}
+	}
+
+	resources, err := JobResources(job)
+	if err != nil {
+		return nil, err
+	}
+
 	return &models.TrainingJobResponse{
-		ID:          job.ID,
-		JobName:     job.JobName,
-		Namespace:   job.Namespace,
-		Algorithm:   job.Algorithm,
-		Request:     &req,
-		Status:      job.Status,
-		Message:     job.Message,
-		CreatedAt:   job.CreatedAt,
-		UpdatedAt:   job.UpdatedAt,
+		ID:               job.ID,
+		JobName:          job.JobName,
+		Namespace:        job.Namespace,
+		Federation:       job.Federation,
+		Algorithm:        job.Algorithm,
+		Priority:         job.Priority,
+		Request:          &req,
+		Status:           job.Status,
+		Message:          job.Message,
+		Admission:        job.Admission,
+		ArtifactLocation: job.ArtifactURI,
+		Placement:        placement,
+		FailoverEvents:   failoverEvents,
+		Resources:        resources,
+		CreatedAt:        job.CreatedAt,
+		UpdatedAt:        job.UpdatedAt,
 	}, nil
 }
 
-// ListActiveJobs lists all jobs that are not in terminal state (Succeeded or Failed)
+// ListActiveJobs lists all jobs that are not in terminal state (Succeeded or Failed) or being deleted
 func (r *Repository) ListActiveJobs() ([]config.TrainingJob, error) {
 	var jobs []config.TrainingJob
-	err := r.db.Where("status NOT IN (?)", []string{"Succeeded", "Failed"}).
+	err := r.db.Where("status NOT IN (?)", []string{"Succeeded", "Failed", "Deleting"}).
+		Order("created_at DESC").
+		Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// ListDeletingJobs lists the jobs whose resources are still being deleted
+func (r *Repository) ListDeletingJobs() ([]config.TrainingJob, error) {
+	var jobs []config.TrainingJob
+	err := r.db.Where("status = ?", "Deleting").
+		Order("created_at DESC").
+		Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// ListActiveJobsByName lists the active jobs with the given name in a namespace.
+// Usually there is at most one, since they share the RayJob name in Karmada.
+func (r *Repository) ListActiveJobsByName(namespace, jobName string) ([]config.TrainingJob, error) {
+	var jobs []config.TrainingJob
+	err := r.db.Where("namespace = ? AND job_name = ? AND status NOT IN (?)", namespace, jobName, []string{"Succeeded", "Failed", "Deleting"}).
 		Order("created_at DESC").
 		Find(&jobs).Error
 	if err != nil {
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"github.com/loiht2/ml-platform-training-job/backend/config"
+	"github.com/loiht2/ml-platform-training-job/backend/models"
+)
+
+// Store persists training jobs. Repository stores them in Postgres;
+// MemoryStore keeps them in memory for tests.
+type Store interface {
+	CreateTrainingJob(req *models.TrainingJobRequest, id string) (*config.TrainingJob, error)
+	GetTrainingJob(id string) (*config.TrainingJob, error)
+	ListTrainingJobs(namespace string) ([]config.TrainingJob, error)
+	ListActiveJobs() ([]config.TrainingJob, error)
+	ListActiveJobsByName(namespace, jobName string) ([]config.TrainingJob, error)
+	ListDeletingJobs() ([]config.TrainingJob, error)
+	UpdateTrainingJobStatus(id, status, message string) error
+	UpdateAdmissionState(id, admission string) error
+	SetArtifactLocation(id, location string) error
+	UpdatePlacement(id string, placement []models.ClusterPlacement) error
+	AppendFailoverEvent(id string, event models.FailoverEvent) error
+	SetJobResources(id string, resources []models.ResourceRef) error
+	DeleteTrainingJob(id string) error
+}
+
+var (
+	_ Store = &Repository{}
+	_ Store = &MemoryStore{}
+)